@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/paq-devs/paq-be-rpg/internal/auth"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+type ErrorResponse struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+var statusByKind = map[lobby_.ErrorKind]int{
+	lobby_.NotFound:      http.StatusNotFound,
+	lobby_.Conflict:      http.StatusConflict,
+	lobby_.Unprocessable: http.StatusUnprocessableEntity,
+	lobby_.Unauthorized:  http.StatusUnauthorized,
+	lobby_.Forbidden:     http.StatusForbidden,
+	lobby_.Expired:       http.StatusGone,
+	lobby_.Unavailable:   http.StatusServiceUnavailable,
+	lobby_.Internal:      http.StatusInternalServerError,
+}
+
+var statusByAuthError = map[error]int{
+	auth.ErrMissingToken: http.StatusUnauthorized,
+	auth.ErrInvalidToken: http.StatusUnauthorized,
+	auth.ErrTokenExpired: http.StatusUnauthorized,
+	auth.ErrWrongLobby:   http.StatusForbidden,
+}
+
+// writeError translates err into an ErrorResponse. Domain and session errors
+// keep their code and get a matching status, anything else is a 500.
+func writeError(w http.ResponseWriter, err error) {
+	for authErr, status := range statusByAuthError {
+		if errors.Is(err, authErr) {
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+
+			writeErrorResponse(w, status, ErrorResponse{
+				Code:    authErr.Error(),
+				Message: "a valid session token for this lobby is required",
+			})
+			return
+		}
+	}
+
+	var domainErr *lobby_.Error
+
+	if !errors.As(err, &domainErr) {
+		log.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, ErrorResponse{
+			Code:    "internal_error",
+			Message: "internal server error",
+		})
+		return
+	}
+
+	status, ok := statusByKind[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	if domainErr.Kind == lobby_.Unavailable {
+		w.Header().Set("Retry-After", "5")
+	}
+
+	writeErrorResponse(w, status, ErrorResponse{
+		Code:    domainErr.Code,
+		Message: domainErr.Message,
+		Details: domainErr.Details,
+	})
+}
+
+// writeBadRequest replies to requests that could not be parsed.
+func writeBadRequest(w http.ResponseWriter, err error) {
+	writeErrorResponse(w, http.StatusBadRequest, ErrorResponse{
+		Code:    "invalid_request",
+		Message: err.Error(),
+	})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, response ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
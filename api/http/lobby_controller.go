@@ -10,37 +10,106 @@ router.HandleFunc("/lobbies/{accessCode}", http.GetLobby).Methods("GET")
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
 )
 
+// ProfileRequest sends the profile to join with, or the profile_id of a
+// stored one along with its profile_secret.
 type ProfileRequest struct {
-	ProfileId  string              `json:"profile_id"`
+	ProfileId     string `json:"profile_id"`
+	ProfileSecret string `json:"profile_secret"` // returned once by POST /profiles
+
 	Avatar     string              `json:"avatar"`
 	Name       string              `json:"name"`
 	HardSkills []profile.HardSkill `json:"hard_skills"`
 	SoftSkills []profile.SoftSkill `json:"soft_skills"`
+
+	HardSkillLevels map[profile.HardSkill]ProficiencyRequest `json:"hard_skill_levels"` // skills left out are at level 3
+	SoftSkillLevels map[profile.SoftSkill]ProficiencyRequest `json:"soft_skill_levels"` // skills left out are at level 3
+}
+
+var errProfileFieldsWithID = errors.New("profile_id can not be sent along with the fields of a profile")
+
+// hasProfileFields reports whether the request sends a profile of its own.
+func (r ProfileRequest) hasProfileFields() bool {
+	return r.Name != "" || r.Avatar != "" || len(r.HardSkills) > 0 || len(r.SoftSkills) > 0 ||
+		len(r.HardSkillLevels) > 0 || len(r.SoftSkillLevels) > 0
+}
+
+// ProficiencyRequest is the self-assessment of a player in one of the skills
+// it declared.
+type ProficiencyRequest struct {
+	Level int    `json:"level"` // from 1, a beginner, to 5, an expert
+	Notes string `json:"notes"`
 }
 
+// The acting leader of a selection is the profile of the session token.
 type SelectPlayerRequest struct {
 	PlayerId string `json:"player_id"`
-	LeaderId string `json:"leader_id"`
 }
 
 type SelectTeamRequest struct {
-	TeamId   int    `json:"team_id"`
-	LeaderId string `json:"leader_id"`
+	TeamId int `json:"team_id"`
 }
 
 type LobbyCreateRequest struct {
-	MasterName    string `json:"master_name"`
-	MasterAvatar  string `json:"master_avatar"`
-	LobbyName     string `json:"name"`
-	MaxHardSkills int    `json:"max_hard_skills"`
-	MaxSoftSkills int    `json:"max_soft_skills"`
+	MasterName         string `json:"master_name"`
+	MasterAvatar       string `json:"master_avatar"`
+	LobbyName          string `json:"name"`
+	MaxHardSkills      int    `json:"max_hard_skills"`
+	MaxSoftSkills      int    `json:"max_soft_skills"`
+	TurnTimeoutSeconds int    `json:"turn_timeout_seconds"` // 0 disables the turn timer
+	DraftOrder         string `json:"draft_order"`          // round_robin (default), snake or reverse_priority
+	TeamFormation      string `json:"team_formation"`       // draft (default) or balanced
+	MaxPlayers         int    `json:"max_players"`          // 0 means no limit, the next players go to the waitlist
+	MaxMentors         int    `json:"max_mentors"`          // 0 means no limit, the next mentors go to the waitlist
+	MinPlayersPerTeam  int    `json:"min_players_per_team"` // 0 means no limit, counts the leader
+	MaxPlayersPerTeam  int    `json:"max_players_per_team"` // 0 means no limit, counts the leader
+
+	PriorityStrategy PriorityStrategyRequest `json:"priority_strategy"`
+	LeaderRule       *LeaderRuleRequest      `json:"leader_rule"`     // GDP or Leadership make a leader when empty
+	SkillCatalogue   *SkillCatalogueRequest  `json:"skill_catalogue"` // the built-in skills when empty
+}
+
+// SkillCatalogueRequest lists the skills the players of the lobby can declare.
+type SkillCatalogueRequest struct {
+	HardSkills []lobby_.SkillDefinition `json:"hard_skills"`
+	SoftSkills []lobby_.SkillDefinition `json:"soft_skills"`
+}
+
+// LeaderRuleRequest chooses which players become leaders when they join.
+type LeaderRuleRequest struct {
+	Mode       string              `json:"mode"` // any_of, all_of or nominated
+	HardSkills []profile.HardSkill `json:"hard_skills"`
+	SoftSkills []profile.SoftSkill `json:"soft_skills"`
+}
+
+// PriorityStrategyRequest chooses how the leaders are ranked for the draft.
+type PriorityStrategyRequest struct {
+	Name             string                    `json:"name"`               // default (when empty), weighted or lottery
+	HardSkillWeights map[profile.HardSkill]int `json:"hard_skill_weights"` // weighted only
+	SoftSkillWeights map[profile.SoftSkill]int `json:"soft_skill_weights"` // weighted only
+	Seed             *int64                    `json:"seed"`               // lottery only, a random seed is used when empty
+}
+
+type BalanceTeamsRequest struct {
+	Seed *int64 `json:"seed"` // a random seed is used when empty
+}
+
+// BalanceTeamsResponse carries the seed used, so the same teams can be
+// generated again, and the skill coverage of every team.
+type BalanceTeamsResponse struct {
+	*lobby_.LobbyResponse
+	Seed     int64                 `json:"seed"`
+	Coverage []lobby_.TeamCoverage `json:"coverage"`
 }
 
 // CreateLobby godoc
@@ -50,7 +119,9 @@ type LobbyCreateRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body LobbyCreateRequest true "Lobby request"
-// @Success 200 {object} LobbyResponse
+// @Success 200 {object} SessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Router /lobbies [post]
 func CreateLobby(w http.ResponseWriter, r *http.Request) {
 	request := LobbyCreateRequest{}
@@ -58,22 +129,107 @@ func CreateLobby(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&request)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
+		return
+	}
+
+	draftOrder, err := lobby_.ParseDraftOrder(request.DraftOrder)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
+	teamFormation, err := lobby_.ParseTeamFormation(request.TeamFormation)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	skillCatalogue := lobby_.DefaultSkillCatalogue()
+	if request.SkillCatalogue != nil {
+		skillCatalogue = lobby_.SkillCatalogue{
+			HardSkills: request.SkillCatalogue.HardSkills,
+			SoftSkills: request.SkillCatalogue.SoftSkills,
+		}
+
+		if err := skillCatalogue.Validate(); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	settings := lobby_.PrioritySettings{
+		Name:             lobby_.PriorityStrategyName(request.PriorityStrategy.Name),
+		HardSkillWeights: request.PriorityStrategy.HardSkillWeights,
+		SoftSkillWeights: request.PriorityStrategy.SoftSkillWeights,
+		Seed:             time.Now().UnixNano(),
+	}
+
+	if request.PriorityStrategy.Seed != nil {
+		settings.Seed = *request.PriorityStrategy.Seed
+	}
+
+	if len(settings.HardSkillWeights)+len(settings.SoftSkillWeights) == 0 { // weigh the skills as the catalogue does
+		settings.HardSkillWeights = skillCatalogue.HardSkillWeights()
+		settings.SoftSkillWeights = skillCatalogue.SoftSkillWeights()
+	}
+
+	priorityStrategy, err := settings.Strategy()
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	leaderRule := lobby_.DefaultLeaderRuleFor(skillCatalogue)
+	if request.LeaderRule != nil {
+		leaderRule = lobby_.LeaderRule{
+			Mode:       lobby_.LeaderRuleMode(request.LeaderRule.Mode),
+			HardSkills: request.LeaderRule.HardSkills,
+			SoftSkills: request.LeaderRule.SoftSkills,
+		}
+	}
+
+	if err := leaderRule.Validate(skillCatalogue); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	capacity := lobby_.Capacity{
+		MaxPlayers:        request.MaxPlayers,
+		MaxMentors:        request.MaxMentors,
+		MinPlayersPerTeam: request.MinPlayersPerTeam,
+		MaxPlayersPerTeam: request.MaxPlayersPerTeam,
+	}
+
+	if err := capacity.Validate(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	master := profile.NewMaster(request.MasterName, request.MasterAvatar)
+
 	lobby, err := config.GetModule().LobbyService.CreateLobby(r.Context(),
-		profile.NewMaster(request.MasterName, request.MasterAvatar),
+		master,
 		request.LobbyName,
 		request.MaxHardSkills,
-		request.MaxSoftSkills)
+		request.MaxSoftSkills,
+		lobby_.WithTurnTimeout(time.Duration(request.TurnTimeoutSeconds)*time.Second),
+		lobby_.WithDraftOrder(draftOrder),
+		lobby_.WithTeamFormation(teamFormation),
+		lobby_.WithCapacity(capacity),
+		lobby_.WithPriorityStrategy(priorityStrategy),
+		lobby_.WithLeaderRule(leaderRule),
+		lobby_.WithSkillCatalogue(skillCatalogue))
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(lobby)
+	writeSession(w, lobby, master)
 }
 
 // GetLobby godoc
@@ -91,13 +247,8 @@ func GetLobby(w http.ResponseWriter, r *http.Request) {
 
 	lobby, err := config.GetModule().LobbyService.GetLobby(r.Context(), accessCode)
 
-	if (err != nil && err.Error() == "lobby_not_found") || lobby == nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -106,13 +257,18 @@ func GetLobby(w http.ResponseWriter, r *http.Request) {
 
 // JoinLobby godoc
 // @Summary Join a lobby
-// @Description Join a lobby by access code
+// @Description Join a lobby by access code, with the stored profile of profile_id and profile_secret or with the profile sent. When the lobby is full the profile goes to the waitlist.
 // @Tags lobbies
 // @Accept json
 // @Produce json
 // @Param accessCode path string true "Access code"
-// @Success 200 {object} LobbyResponse
+// @Success 200 {object} SessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/join [post]
 func JoinLobby(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
@@ -121,23 +277,44 @@ func JoinLobby(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&request)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
-	lobby, err := config.GetModule().LobbyService.JoinLobby(r.Context(), accessCode, profile.NewPlayer(request.Name, request.Avatar, request.HardSkills, request.SoftSkills))
+	player := profile.NewPlayer(request.Name, request.Avatar, request.HardSkills, request.SoftSkills)
 
-	if (err != nil && err.Error() == "lobby_not_found") || lobby == nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+	for skill, proficiency := range toProficiencies(request.HardSkillLevels) {
+		player.SetHardSkillLevel(skill, proficiency)
+	}
+
+	for skill, proficiency := range toProficiencies(request.SoftSkillLevels) {
+		player.SetSoftSkillLevel(skill, proficiency)
+	}
+
+	if request.ProfileId != "" {
+		if request.hasProfileFields() {
+			writeBadRequest(w, errProfileFieldsWithID)
+			return
+		}
+
+		identity, err := config.GetModule().ProfileService.AuthenticateIdentity(r.Context(), request.ProfileId, request.ProfileSecret)
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		player = identity.Player()
 	}
 
+	lobby, err := config.GetModule().LobbyService.JoinLobby(r.Context(), accessCode, player)
+
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(lobby)
+	writeSession(w, lobby, player)
 }
 
 // SelectPlayer godoc
@@ -149,6 +326,10 @@ func JoinLobby(w http.ResponseWriter, r *http.Request) {
 // @Param accessCode path string true "Access code"
 // @Success 200 {object} LobbyResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/select/player [post]
 func SelectPlayer(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
@@ -157,21 +338,20 @@ func SelectPlayer(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&request)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
-	lobby, err := config.GetModule().LobbyService.SelectPlayer(r.Context(), accessCode, profile.Profile{
-		ID: request.LeaderId,
-	}, request.PlayerId)
-
+	leader, err := sessionProfile(r, accessCode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	if lobby == nil {
-		http.Error(w, "lobby not found", http.StatusNotFound)
+	lobby, err := config.GetModule().LobbyService.SelectPlayer(r.Context(), accessCode, leader, request.PlayerId)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -187,6 +367,10 @@ func SelectPlayer(w http.ResponseWriter, r *http.Request) {
 // @Param accessCode path string true "Access code"
 // @Success 200 {object} LobbyResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/select/team [post]
 func SelectTeam(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
@@ -195,21 +379,51 @@ func SelectTeam(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&request)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
+		return
+	}
+
+	leader, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lobby, err := config.GetModule().LobbyService.SelectTeam(r.Context(), accessCode, leader, request.TeamId)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	lobby, err := config.GetModule().LobbyService.SelectTeam(r.Context(), accessCode, profile.Profile{
-		ID: request.LeaderId,
-	}, request.TeamId)
+	json.NewEncoder(w).Encode(lobby)
+}
+
+// UndoSelection godoc
+// @Summary Undo the last selection
+// @Description Revert the most recent team or player selection of the current phase and give the turn back to the leader who made it
+// @Tags lobbies
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Success 200 {object} LobbyResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/undo [post]
+func UndoSelection(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
 
+	master, err := sessionProfile(r, accessCode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	if lobby == nil {
-		http.Error(w, "lobby not found", http.StatusNotFound)
+	lobby, err := config.GetModule().LobbyService.UndoSelection(r.Context(), accessCode, master)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -218,13 +432,17 @@ func SelectTeam(w http.ResponseWriter, r *http.Request) {
 
 // JoinAsMentor godoc
 // @Summary Join as mentor
-// @Description Join as mentor by access code
+// @Description Join as mentor by access code, with the stored profile of profile_id and profile_secret or with the profile sent
 // @Tags lobbies
 // @Accept json
 // @Produce json
 // @Param accessCode path string true "Access code"
-// @Success 200 {object} LobbyResponse
+// @Success 200 {object} SessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/mentor [post]
 func JoinMentor(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
@@ -233,18 +451,125 @@ func JoinMentor(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&request)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
-	lobby, err := config.GetModule().LobbyService.JoinLobby(r.Context(), accessCode, profile.NewMentor(request.Name, request.Avatar))
+	mentor := profile.NewMentor(request.Name, request.Avatar)
+
+	if request.ProfileId != "" {
+		if request.hasProfileFields() {
+			writeBadRequest(w, errProfileFieldsWithID)
+			return
+		}
+
+		identity, err := config.GetModule().ProfileService.AuthenticateIdentity(r.Context(), request.ProfileId, request.ProfileSecret)
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		mentor = identity.Mentor()
+	}
+
+	lobby, err := config.GetModule().LobbyService.JoinLobby(r.Context(), accessCode, mentor)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	if lobby == nil {
-		http.Error(w, "lobby not found", http.StatusNotFound)
+	writeSession(w, lobby, mentor)
+}
+
+// LeaveLobby godoc
+// @Summary Leave a lobby
+// @Description Remove the profile of the session token from a lobby still waiting for players. It can rejoin later.
+// @Tags lobbies
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Success 200 {object} LobbyResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/leave [post]
+func LeaveLobby(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	p, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lobby, err := config.GetModule().LobbyService.LeaveLobby(r.Context(), accessCode, p)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(lobby)
+}
+
+// RejoinLobby godoc
+// @Summary Rejoin a lobby
+// @Description Restore the profile of the session token after it left the lobby, keeping its original join time
+// @Tags lobbies
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Success 200 {object} SessionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/rejoin [post]
+func RejoinLobby(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	p, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lobby, err := config.GetModule().LobbyService.RejoinLobby(r.Context(), accessCode, p)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeSession(w, lobby, p)
+}
+
+// KickProfile godoc
+// @Summary Kick a profile
+// @Description Remove a player or mentor from a lobby still waiting for players. The profile can not come back.
+// @Tags lobbies
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Param profileId path string true "Profile id"
+// @Success 200 {object} LobbyResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/kick/{profileId} [post]
+func KickProfile(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+	profileId := mux.Vars(r)["profileId"]
+
+	master, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lobby, err := config.GetModule().LobbyService.KickFromLobby(r.Context(), accessCode, master, profileId)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -260,18 +585,24 @@ func JoinMentor(w http.ResponseWriter, r *http.Request) {
 // @Param accessCode path string true "Access code"
 // @Success 200 {object} LobbyResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/close [post]
 func CloseLobby(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
-	lobby, err := config.GetModule().LobbyService.StartTeamCreation(r.Context(), accessCode)
 
+	master, err := sessionProfile(r, accessCode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	if lobby == nil {
-		http.Error(w, "lobby not found", http.StatusNotFound)
+	lobby, err := config.GetModule().LobbyService.StartTeamCreation(r.Context(), accessCode, master)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -288,24 +619,163 @@ func CloseLobby(w http.ResponseWriter, r *http.Request) {
 // @Param playerId path string true "Player id"
 // @Success 200 {object} LobbyResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Router /lobbies/{accessCode}/promote/{playerId} [post]
 func PromotePlayer(w http.ResponseWriter, r *http.Request) {
 	accessCode := mux.Vars(r)["accessCode"]
 	playerId := mux.Vars(r)["playerId"]
 
-	lobby, err := config.GetModule().LobbyService.PromoteLeader(r.Context(), accessCode, profile.Profile{
+	master, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lobby, err := config.GetModule().LobbyService.PromoteLeader(r.Context(), accessCode, master, profile.Profile{
 		ID: playerId,
 	})
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(lobby)
+}
+
+// GetLobbyActions godoc
+// @Summary List available actions
+// @Description List the actions the caller can do in the lobby right now, with the statuses each one can lead to. Without a session token the caller is a visitor.
+// @Tags lobbies
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Success 200 {array} lobby_.AvailableAction
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/actions [get]
+func GetLobbyActions(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	caller := profile.Profile{}
+	if r.Header.Get("Authorization") != "" {
+		var err error
+		caller, err = sessionProfile(r, accessCode)
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	actions, err := config.GetModule().LobbyService.AvailableActions(r.Context(), accessCode, caller)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(actions)
+}
+
+// ChangeLobbyStatus godoc
+// @Summary Change the status of a session
+// @Description Start, pause, resume, finish or archive the session of a lobby
+// @Tags lobbies
+// @Accept json
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Param action path string true "Action" Enums(start, pause, resume, finish, archive)
+// @Success 200 {object} LobbyResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/{action} [post]
+func ChangeLobbyStatus(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	action, err := lobby_.ParseLobbyAction(mux.Vars(r)["action"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	master, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	if lobby == nil {
-		http.Error(w, "lobby not found", http.StatusNotFound)
+	lobby, err := config.GetModule().LobbyService.ChangeStatus(r.Context(), accessCode, master, action)
+
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
 	json.NewEncoder(w).Encode(lobby)
 }
+
+// BalanceTeams godoc
+// @Summary Generate balanced teams
+// @Description Fill the teams of a lobby created with the balanced team formation, spreading skills across teams. The same seed always generates the same teams.
+// @Tags lobbies
+// @Accept json
+// @Produce json
+// @Param accessCode path string true "Access code"
+// @Param request body BalanceTeamsRequest false "Seed"
+// @Success 200 {object} BalanceTeamsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/teams/balance [post]
+func BalanceTeams(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+	request := BalanceTeamsRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+
+	if err != nil && err != io.EOF { // the body is optional
+		writeBadRequest(w, err)
+		return
+	}
+
+	master, err := sessionProfile(r, accessCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	seed := time.Now().UnixNano()
+	if request.Seed != nil {
+		seed = *request.Seed
+	}
+
+	lobby, coverage, err := config.GetModule().LobbyService.BalanceTeams(r.Context(), accessCode, master, seed)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(BalanceTeamsResponse{
+		LobbyResponse: lobby,
+		Seed:          seed,
+		Coverage:      coverage,
+	})
+}
+
+func writeSession(w http.ResponseWriter, lobby *lobby_.LobbyResponse, p profile.Profile) {
+	session, err := newSessionResponse(lobby, p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(session)
+}
@@ -0,0 +1,11 @@
+package http
+
+import "strconv"
+
+func parseSequence(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// ListLobbies godoc
+// @Summary List lobbies
+// @Description List the lobbies newest first, a page at a time, each summarised with its members counted. Send the next_cursor of a page as cursor to get the next one; the last page has none. A master, authenticated by the session token of any of their lobbies, only gets their own lobbies; the admin token lists every lobby. Access codes are only shown to the master of the lobby and to the admin.
+// @Tags lobbies
+// @Produce json
+// @Param Authorization header string false "Bearer session token of a master"
+// @Param X-Admin-Token header string false "Admin token, PAQ_ADMIN_TOKEN"
+// @Param status query string false "Only lobbies in this status"
+// @Param master_id query string false "Only lobbies of this master, replaced by the caller for masters"
+// @Param name query string false "Only lobbies with this text in the name, ignoring case"
+// @Param created_from query int false "Only lobbies created at or after this unix timestamp"
+// @Param created_to query int false "Only lobbies created before this unix timestamp"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Lobbies per page, 20 by default and at most 100"
+// @Success 200 {object} lobby_.LobbySummaryPageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /lobbies [get]
+func ListLobbies(w http.ResponseWriter, r *http.Request) {
+	filter, page, err := parseLobbyListQuery(r.URL.Query())
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	admin := config.GetModule().Admin.Allows(r.Header.Get("X-Admin-Token"))
+
+	if !admin {
+		caller, err := sessionProfile(r, "")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if caller.Role != profile.Master {
+			writeError(w, lobby_.ErrNotMaster)
+			return
+		}
+
+		filter.MasterID = caller.ID
+	}
+
+	lobbies, err := config.GetModule().LobbyService.ListLobbies(r.Context(), filter, page)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if !admin {
+		lobbies.HideAccessCodes(filter.MasterID)
+	}
+
+	json.NewEncoder(w).Encode(lobbies)
+}
+
+func parseLobbyListQuery(query url.Values) (lobby_.LobbyFilter, lobby_.PageRequest, error) {
+	filter := lobby_.LobbyFilter{
+		Status:   lobby_.LobbyStatus(query.Get("status")),
+		MasterID: query.Get("master_id"),
+		Name:     query.Get("name"),
+	}
+	page := lobby_.PageRequest{Cursor: query.Get("cursor")}
+
+	if filter.Status != "" && !filter.Status.Known() {
+		return filter, page, fmt.Errorf("status %q is not a lobby status", filter.Status)
+	}
+
+	integers := []struct {
+		name  string
+		value *int64
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+	}
+
+	for _, integer := range integers {
+		if value := query.Get(integer.name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return filter, page, fmt.Errorf("%s must be a unix timestamp", integer.name)
+			}
+
+			*integer.value = parsed
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, page, errors.New("limit must be a number")
+		}
+
+		page.Limit = limit
+	}
+
+	return filter, page, nil
+}
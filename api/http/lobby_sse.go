@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+const (
+	sseKeepAliveInterval = 15 * time.Second
+	sseRetry             = 3 * time.Second
+)
+
+// LobbyEventStream godoc
+// @Summary Lobby event stream (SSE)
+// @Description Stream lobby snapshots and events as Server-Sent Events. Honors Last-Event-ID to resume after a reconnect.
+// @Tags lobbies
+// @Produce text/event-stream
+// @Param accessCode path string true "Access code"
+// @Param Last-Event-ID header int false "Last received sequence number"
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/events [get]
+func LobbyEventStream(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+	service := config.GetModule().LobbyService
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("streaming unsupported"))
+		return
+	}
+
+	since, err := parseSequence(r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	subscription, missed, err := service.SubscribeEvents(r.Context(), accessCode, since)
+
+	resync := since == 0
+	if errors.Is(err, lobby_.ErrEventsExpired) { // too far behind, start over from a fresh snapshot
+		resync = true
+		subscription, missed, err = service.SubscribeEvents(r.Context(), accessCode, 0)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+	if resync {
+		lobby, err := service.GetLobby(r.Context(), accessCode)
+		if err != nil {
+			return
+		}
+
+		if writeSSE(w, "", lobby_.LobbyUpdated, lobby) != nil {
+			return
+		}
+	}
+
+	for _, event := range missed {
+		if writeSSE(w, fmt.Sprint(event.Sequence), event.Type, event) != nil {
+			return
+		}
+	}
+
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok { // dropped by the broker, the browser reconnects with Last-Event-ID
+				return
+			}
+
+			if writeSSE(w, fmt.Sprint(event.Sequence), event.Type, event) != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w http.ResponseWriter, id string, eventType lobby_.LobbyEventType, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, payload)
+	return err
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+var timelineCSVHeader = []string{
+	"position", "phase", "type",
+	"actor_id", "actor_name",
+	"target_kind", "target_id", "target_name",
+	"before_choosing_now", "before_deadline",
+	"after_type", "after_choosing_now", "after_deadline",
+	"timestamp", "elapsed_seconds",
+}
+
+// GetLobbyTimeline godoc
+// @Summary Draft timeline
+// @Description List the selections of the draft in order, with who chose what, the turn before and after each choice and how long each turn took. Send ?format=csv or Accept: text/csv to download it as CSV.
+// @Tags lobbies
+// @Produce json
+// @Produce text/csv
+// @Param accessCode path string true "Access code"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {array} lobby_.TimelineEntry
+// @Failure 404 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/timeline [get]
+func GetLobbyTimeline(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	timeline, err := config.GetModule().LobbyService.Timeline(r.Context(), accessCode)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if !wantsCSV(r) {
+		json.NewEncoder(w).Encode(timeline)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "timeline-"+accessCode+".csv"))
+	writeTimelineCSV(w, timeline)
+}
+
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "csv"
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+func writeTimelineCSV(w http.ResponseWriter, timeline []lobby_.TimelineEntry) {
+	writer := csv.NewWriter(w)
+	writer.Write(timelineCSVHeader)
+
+	for _, entry := range timeline {
+		after := []string{"", "", ""}
+		if entry.After != nil {
+			after = []string{string(entry.After.Type), entry.After.ChoosingNow.ID, formatDeadline(entry.After.Deadline)}
+		}
+
+		record := []string{
+			strconv.Itoa(entry.Position), string(entry.Phase), string(entry.Type),
+			entry.Actor.ID, entry.Actor.Name,
+			entry.Target.Kind, entry.Target.ID, entry.Target.Name,
+			entry.Before.ChoosingNow.ID, formatDeadline(entry.Before.Deadline),
+		}
+		record = append(record, after...)
+		record = append(record, strconv.FormatInt(entry.Timestamp, 10), strconv.FormatInt(entry.ElapsedSeconds, 10))
+
+		writer.Write(record)
+	}
+
+	writer.Flush()
+}
+
+func formatDeadline(deadline int64) string {
+	if deadline == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(deadline, 10)
+}
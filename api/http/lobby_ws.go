@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+const (
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = (wsPongWait * 9) / 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return config.GetModule().Origins.Allows(r.Header.Get("Origin"))
+	},
+}
+
+// LobbyWebSocket godoc
+// @Summary Lobby event stream
+// @Description Push lobby events over a WebSocket. Pass since to resume after the last received sequence number.
+// @Tags lobbies
+// @Param accessCode path string true "Access code"
+// @Param since query int false "Last received sequence number"
+// @Success 101
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /lobbies/{accessCode}/ws [get]
+func LobbyWebSocket(w http.ResponseWriter, r *http.Request) {
+	accessCode := mux.Vars(r)["accessCode"]
+
+	since, err := parseSequence(r.URL.Query().Get("since"))
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	subscription, missed, err := config.GetModule().LobbyService.SubscribeEvents(r.Context(), accessCode, since)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	defer subscription.Close()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader already replied to the client
+	}
+
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go readUntilClosed(conn, done)
+
+	for _, event := range missed {
+		if writeEvent(conn, event) != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok { // dropped by the broker, the client resumes from its last sequence
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resubscribe"),
+					time.Now().Add(wsWriteWait))
+				return
+			}
+
+			if writeEvent(conn, event) != nil {
+				return
+			}
+		case <-ticker.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+			if err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// readUntilClosed consumes client frames so pongs and close messages are
+// processed, and closes done when the connection goes away.
+func readUntilClosed(conn *websocket.Conn, done chan struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(conn *websocket.Conn, event lobby_.LobbyEvent) error {
+	conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return conn.WriteJSON(event)
+}
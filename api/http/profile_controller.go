@@ -0,0 +1,150 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+type CreateProfileRequest struct {
+	Name       string              `json:"name"`
+	Avatar     string              `json:"avatar"`
+	HardSkills []profile.HardSkill `json:"hard_skills"`
+	SoftSkills []profile.SoftSkill `json:"soft_skills"`
+
+	HardSkillLevels map[profile.HardSkill]ProficiencyRequest `json:"hard_skill_levels"` // skills left out are at level 3
+	SoftSkillLevels map[profile.SoftSkill]ProficiencyRequest `json:"soft_skill_levels"` // skills left out are at level 3
+}
+
+// UpdateProfileRequest changes the fields sent and leaves the others as they
+// are. Levels are merged into the current ones.
+type UpdateProfileRequest struct {
+	Name       *string              `json:"name"`
+	Avatar     *string              `json:"avatar"`
+	HardSkills *[]profile.HardSkill `json:"hard_skills"` // replaces the hard skills, dropping the levels of the ones removed
+	SoftSkills *[]profile.SoftSkill `json:"soft_skills"` // replaces the soft skills, dropping the levels of the ones removed
+
+	HardSkillLevels map[profile.HardSkill]ProficiencyRequest `json:"hard_skill_levels"`
+	SoftSkillLevels map[profile.SoftSkill]ProficiencyRequest `json:"soft_skill_levels"`
+}
+
+// CreateProfile godoc
+// @Summary Create a profile
+// @Description Create a profile that can join any number of lobbies by its id
+// @Tags profiles
+// @Accept json
+// @Produce json
+// @Param request body CreateProfileRequest true "Profile request"
+// @Success 201 {object} lobby_.CreatedIdentityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /profiles [post]
+func CreateProfile(w http.ResponseWriter, r *http.Request) {
+	request := CreateProfileRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	p := profile.NewPlayer(request.Name, request.Avatar, request.HardSkills, request.SoftSkills)
+
+	for skill, proficiency := range toProficiencies(request.HardSkillLevels) {
+		p.SetHardSkillLevel(skill, proficiency)
+	}
+
+	for skill, proficiency := range toProficiencies(request.SoftSkillLevels) {
+		p.SetSoftSkillLevel(skill, proficiency)
+	}
+
+	response, err := config.GetModule().ProfileService.CreateProfile(r.Context(), p.Identity())
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetProfile godoc
+// @Summary Get a profile
+// @Description Get a profile by id
+// @Tags profiles
+// @Produce json
+// @Param id path string true "Profile id"
+// @Success 200 {object} lobby_.IdentityResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /profiles/{id} [get]
+func GetProfile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	response, err := config.GetModule().ProfileService.GetProfile(r.Context(), id)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateProfile godoc
+// @Summary Update a profile
+// @Description Change the fields sent of a profile. Lobbies joined before keep the profile as it was.
+// @Tags profiles
+// @Accept json
+// @Produce json
+// @Param id path string true "Profile id"
+// @Param X-Profile-Secret header string true "Secret returned when the profile was created"
+// @Param request body UpdateProfileRequest true "Profile changes"
+// @Success 200 {object} lobby_.IdentityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /profiles/{id} [patch]
+func UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	request := UpdateProfileRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	response, err := config.GetModule().ProfileService.UpdateProfile(r.Context(), id, r.Header.Get("X-Profile-Secret"), lobby_.ProfilePatch{
+		Name:            request.Name,
+		Avatar:          request.Avatar,
+		HardSkills:      request.HardSkills,
+		SoftSkills:      request.SoftSkills,
+		HardSkillLevels: toProficiencies(request.HardSkillLevels),
+		SoftSkillLevels: toProficiencies(request.SoftSkillLevels),
+	})
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func toProficiencies[S profile.HardSkill | profile.SoftSkill](requests map[S]ProficiencyRequest) map[S]profile.Proficiency {
+	levels := make(map[S]profile.Proficiency, len(requests))
+	for skill, request := range requests {
+		levels[skill] = profile.Proficiency{Level: profile.SkillLevel(request.Level), Notes: request.Notes}
+	}
+
+	return levels
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/paq-devs/paq-be-rpg/config"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// SessionResponse is returned when a profile enters a lobby. The token must
+// be sent as "Authorization: Bearer <token>" on every action of that profile.
+type SessionResponse struct {
+	*lobby_.LobbyResponse
+	ProfileID string `json:"profile_id"`
+	Token     string `json:"token"`
+}
+
+func newSessionResponse(lobby *lobby_.LobbyResponse, p profile.Profile) (*SessionResponse, error) {
+	token, err := config.GetModule().Sessions.Issue(p, lobby.AccessCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionResponse{
+		LobbyResponse: lobby,
+		ProfileID:     p.ID,
+		Token:         token,
+	}, nil
+}
+
+// sessionProfile returns the profile authenticated by the request token for
+// the lobby.
+func sessionProfile(r *http.Request, accessCode string) (profile.Profile, error) {
+	token := ""
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+
+	claims, err := config.GetModule().Sessions.Verify(token, accessCode)
+	if err != nil {
+		return profile.Profile{}, err
+	}
+
+	return profile.Profile{
+		ID:   claims.ProfileID,
+		Role: claims.Role,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+// MemoryLobbyEventStore keeps the events in memory, for local development
+// and tests.
+type MemoryLobbyEventStore struct {
+	mu     sync.RWMutex
+	events map[string][]lobby_.LobbyEvent // by access code
+	stored map[eventKey]bool
+}
+
+// eventKey identifies an event as the unique index of MongoLobbyEventStore
+// does.
+type eventKey struct {
+	accessCode string
+	version    int
+	index      int
+}
+
+func NewMemoryLobbyEventStore() *MemoryLobbyEventStore {
+	return &MemoryLobbyEventStore{
+		events: make(map[string][]lobby_.LobbyEvent),
+		stored: make(map[eventKey]bool),
+	}
+}
+
+func (s *MemoryLobbyEventStore) Append(ctx context.Context, events ...lobby_.LobbyEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		key := eventKey{event.AccessCode, event.Version, event.Index}
+		if s.stored[key] {
+			continue
+		}
+
+		s.stored[key] = true
+		s.events[event.AccessCode] = append(s.events[event.AccessCode], event)
+	}
+
+	return nil
+}
+
+func (s *MemoryLobbyEventStore) Load(ctx context.Context, accessCode string) ([]lobby_.LobbyEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]lobby_.LobbyEvent, len(s.events[accessCode]))
+	copy(events, s.events[accessCode])
+	return events, nil
+}
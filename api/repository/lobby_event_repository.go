@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLobbyEventStore keeps the event log of the lobbies in its own
+// collection, one document per event. Documents are only ever inserted, and
+// the unique index of EnsureIndexes keeps an event appended twice from being
+// stored twice.
+type MongoLobbyEventStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLobbyEventStore(db *mongo.Database, collectionName string) *MongoLobbyEventStore {
+	return &MongoLobbyEventStore{
+		collection: db.Collection(collectionName),
+	}
+}
+
+func (s *MongoLobbyEventStore) Append(ctx context.Context, events ...lobby_.LobbyEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(events))
+	for i, event := range events {
+		document, err := NewLobbyEventBson(event)
+		if err != nil {
+			return err
+		}
+
+		documents[i] = document
+	}
+
+	// unordered, so the events already stored do not stop the ones after them
+	_, err := s.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if onlyDuplicates(err) {
+		return nil
+	}
+
+	return err
+}
+
+// onlyDuplicates reports whether every write of a bulk insert that failed
+// was rejected by a unique index.
+func onlyDuplicates(err error) bool {
+	var bulk mongo.BulkWriteException
+	if !errors.As(err, &bulk) || bulk.WriteConcernError != nil || len(bulk.WriteErrors) == 0 {
+		return false
+	}
+
+	for _, writeErr := range bulk.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+
+	return true
+}
+
+const duplicateKeyCode = 11000
+
+// eventIndexes back Load and keep each event of a lobby version stored once.
+var eventIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "accessCode", Value: 1}, {Key: "version", Value: 1}, {Key: "index", Value: 1}},
+		Options: options.Index().SetName("accessCode_version_index_unique").SetUnique(true),
+	},
+}
+
+// EnsureIndexes creates the indexes of the event collection that do not
+// exist yet.
+func (s *MongoLobbyEventStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, eventIndexes)
+	return err
+}
+
+func (s *MongoLobbyEventStore) Load(ctx context.Context, accessCode string) ([]lobby_.LobbyEvent, error) {
+	filter := bson.M{"accessCode": accessCode}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}, {Key: "index", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []LobbyEventBson
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	events := make([]lobby_.LobbyEvent, len(documents))
+	for i, document := range documents {
+		event, err := document.ToLobbyEvent()
+		if err != nil {
+			return nil, err
+		}
+
+		events[i] = event
+	}
+
+	return events, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+func TestMemoryLobbyEventStore(t *testing.T) {
+	testLobbyEventStore(t, NewMemoryLobbyEventStore())
+}
+
+func TestSQLLobbyEventStore(t *testing.T) {
+	testLobbyEventStore(t, NewSQLLobbyEventStore(openTestSQLite(t), SQLite))
+}
+
+func TestPostgresLobbyEventStore(t *testing.T) {
+	testLobbyEventStore(t, NewSQLLobbyEventStore(openTestPostgres(t, testPostgresDSN(t)), Postgres))
+}
+
+// testLobbyEventStore appends an event twice, as a redelivered outbox does,
+// and expects it once with its payload.
+func testLobbyEventStore(t *testing.T, store lobby_.LobbyEventStore) {
+	ctx := context.Background()
+
+	joined := lobby_.LobbyEvent{
+		Type:       lobby_.PlayerJoined,
+		AccessCode: "ABC234",
+		Actor:      "player",
+		Version:    2,
+		Index:      0,
+		Timestamp:  100,
+		Payload:    lobby_.ProfileResponse{ID: "player", Name: "Player"},
+	}
+	changed := lobby_.LobbyEvent{
+		Type:       lobby_.StatusChanged,
+		AccessCode: "ABC234",
+		Version:    2,
+		Index:      1,
+		Payload:    lobby_.StatusChangedPayload{From: lobby_.Waiting, To: lobby_.CreatingTeam},
+	}
+
+	if err := store.Append(ctx, joined); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := store.Append(ctx, joined, changed); err != nil {
+		t.Fatalf("Expected the stored event to be skipped, got %v", err)
+	}
+
+	events, err := store.Load(ctx, "ABC234")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(events) != 2 || events[0].Type != lobby_.PlayerJoined || events[1].Type != lobby_.StatusChanged {
+		t.Fatalf("Expected each event once, in order, got %+v", events)
+	}
+
+	if payload, ok := events[0].Payload.(lobby_.ProfileResponse); !ok || payload.Name != "Player" || events[0].Actor != "player" || events[0].Timestamp != 100 {
+		t.Errorf("Expected the event as appended, got %+v", events[0])
+	}
+
+	if others, _ := store.Load(ctx, "OTHER1"); len(others) != 0 {
+		t.Errorf("Expected no events for another lobby, got %+v", others)
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+// SQLLobbyEventStore keeps the event log of the lobbies in the lobby_events
+// table, one row per event, with the payload as JSON. Rows are only ever
+// inserted, and the primary key keeps an event appended twice from being
+// stored twice.
+type SQLLobbyEventStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLLobbyEventStore expects a database already migrated with MigrateSQL.
+func NewSQLLobbyEventStore(db *sql.DB, dialect SQLDialect) *SQLLobbyEventStore {
+	return &SQLLobbyEventStore{db: db, dialect: dialect}
+}
+
+func (s *SQLLobbyEventStore) Append(ctx context.Context, events ...lobby_.LobbyEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, s.dialect.rebind(`INSERT INTO lobby_events (
+			access_code, version, event_index, type, actor, occurred_at, payload
+		) VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT DO NOTHING`),
+			event.AccessCode, event.Version, event.Index, event.Type, event.Actor, event.Timestamp, string(payload))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLLobbyEventStore) Load(ctx context.Context, accessCode string) ([]lobby_.LobbyEvent, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(`SELECT
+		version, event_index, type, actor, occurred_at, payload
+	FROM lobby_events WHERE access_code = ? ORDER BY version, event_index`), accessCode)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]lobby_.LobbyEvent, 0)
+	for rows.Next() {
+		event := lobby_.LobbyEvent{AccessCode: accessCode}
+		var payload string
+		if err := rows.Scan(&event.Version, &event.Index, &event.Type, &event.Actor, &event.Timestamp, &payload); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if err := decodeSQLPayload(&event, payload); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, closeRows(rows)
+}
+
+// decodeSQLPayload sets the payload of the event from the JSON stored with
+// it.
+func decodeSQLPayload(event *lobby_.LobbyEvent, payload string) error {
+	decoded, err := lobby_.DecodeEventPayload(event.Type, func(v interface{}) error {
+		return json.Unmarshal([]byte(payload), v)
+	})
+	if err != nil {
+		return err
+	}
+
+	event.Payload = decoded
+	return nil
+}
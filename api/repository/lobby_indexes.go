@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lobbyIndexes back FindByAccessCode, which the unique index also keeps from
+// ever matching two lobbies, and List by status, master and creation time.
+var lobbyIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "accessCode", Value: 1}},
+		Options: options.Index().SetName("accessCode_unique").SetUnique(true),
+	},
+	{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("status_createdAt"),
+	},
+	{
+		Keys:    bson.D{{Key: "master.id", Value: 1}, {Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("masterId_createdAt"),
+	},
+	{
+		Keys:    bson.D{{Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("createdAt"),
+	},
+}
+
+// EnsureIndexes creates the indexes of the lobby collection that do not
+// exist yet. It fails when lobbies already stored share an access code, which
+// must be resolved by hand before the unique index can be built.
+func (r *MongoLobbyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, lobbyIndexes)
+	return err
+}
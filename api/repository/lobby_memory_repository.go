@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MemoryLobbyRepository keeps the lobbies in memory, for local development
+// and tests. Lobbies are stored encoded as the documents MongoLobbyRepository
+// writes, so every lobby returned is a deep copy that reads back exactly as
+// it would from MongoDB.
+type MemoryLobbyRepository struct {
+	mu          sync.RWMutex
+	documents   map[string][]byte // by lobby ID
+	accessCodes map[string]string // lobby ID by access code
+}
+
+func NewMemoryLobbyRepository() *MemoryLobbyRepository {
+	return &MemoryLobbyRepository{
+		documents:   make(map[string][]byte),
+		accessCodes: make(map[string]string),
+	}
+}
+
+func (r *MemoryLobbyRepository) Save(ctx context.Context, lobby *lobby_.Lobby) error {
+	encoded, err := NewLobbyBson(lobby)
+	if err != nil {
+		return err
+	}
+
+	document, err := bson.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.documents[lobby.ID]; ok {
+		return fmt.Errorf("lobby %s is already stored", lobby.ID)
+	}
+
+	if _, ok := r.accessCodes[lobby.AccessCode]; ok {
+		return lobby_.ErrAccessCodeTaken.WithDetails(map[string]interface{}{"access_code": lobby.AccessCode})
+	}
+
+	r.documents[lobby.ID] = document
+	r.accessCodes[lobby.AccessCode] = lobby.ID
+	return nil
+}
+
+func (r *MemoryLobbyRepository) FindByAccessCode(ctx context.Context, accessCode string) (*lobby_.Lobby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.accessCodes[accessCode]
+	if !ok {
+		return nil, lobby_.ErrLobbyNotFound
+	}
+
+	return decodeLobby(r.documents[id])
+}
+
+func (r *MemoryLobbyRepository) FindWithTurnDeadline(ctx context.Context) ([]*lobby_.Lobby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lobbies := make([]*lobby_.Lobby, 0)
+	for _, document := range r.documents {
+		lobby, err := decodeLobby(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if lobby.ChooseControl != nil && lobby.ChooseControl.Deadline > 0 {
+			lobbies = append(lobbies, lobby)
+		}
+	}
+
+	return lobbies, nil
+}
+
+func (r *MemoryLobbyRepository) List(ctx context.Context, filter lobby_.LobbyFilter, page lobby_.PageRequest) (lobby_.LobbyPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lobbies := make([]*lobby_.Lobby, 0, len(r.documents))
+	for _, document := range r.documents {
+		lobby, err := decodeLobby(document)
+		if err != nil {
+			return lobby_.LobbyPage{}, err
+		}
+
+		lobbies = append(lobbies, lobby)
+	}
+
+	return lobby_.PageOf(lobbies, filter, page)
+}
+
+func (r *MemoryLobbyRepository) Update(ctx context.Context, lobby *lobby_.Lobby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.documents[lobby.ID]
+	if !ok {
+		return &lobby_.VersionConflictError{LobbyID: lobby.ID, Version: lobby.Version} // as MongoDB, which matches no document
+	}
+
+	var current LobbyBson
+	if err := bson.Unmarshal(stored, &current); err != nil {
+		return err
+	}
+
+	if current.Version != lobby.Version {
+		return &lobby_.VersionConflictError{LobbyID: lobby.ID, Version: lobby.Version}
+	}
+
+	updated, err := NewLobbyBson(lobby)
+	if err != nil {
+		return err
+	}
+	updated.Version = lobby.Version + 1
+
+	document, err := bson.Marshal(updated)
+	if err != nil {
+		return err
+	}
+
+	r.documents[lobby.ID] = document
+	lobby.Version = updated.Version
+	return nil
+}
+
+func decodeLobby(document []byte) (*lobby_.Lobby, error) {
+	var lobby LobbyBson
+	if err := bson.Unmarshal(document, &lobby); err != nil {
+		return nil, err
+	}
+
+	return lobby.ToLobby()
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LobbySchemaVersion is the version of the lobby documents NewLobbyBson
+// writes. ToLobby reads the older ones as well, and Migrate rewrites them.
+//
+//  1. profiles have skill levels, DefaultSkillLevel for the existing skills
+//  2. lobbies have createdAt, indexed by EnsureIndexes
+const LobbySchemaVersion = 2
+
+// Migrate rewrites, mapped as ToLobby does, the lobby documents stored with
+// an older schema and returns how many it rewrote. A lobby updated in the
+// meantime is left alone, the update already stored it with the current
+// schema.
+func (r *MongoLobbyRepository) Migrate(ctx context.Context) (int, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"schemaVersion": bson.M{"$lt": LobbySchemaVersion}},
+		bson.M{"schemaVersion": bson.M{"$exists": false}},
+	}}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var document LobbyBson
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, err
+		}
+
+		lobby, err := document.ToLobby()
+		if err != nil {
+			return migrated, err
+		}
+
+		replacement, err := NewLobbyBson(lobby)
+		if err != nil {
+			return migrated, err
+		}
+
+		result, err := r.collection.ReplaceOne(ctx, versionFilter(document.ID, document.Version), replacement)
+		if err != nil {
+			return migrated, err
+		}
+
+		migrated += int(result.ModifiedCount)
+	}
+
+	return migrated, cursor.Err()
+}
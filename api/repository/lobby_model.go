@@ -1,19 +1,29 @@
 package repository
 
 import (
+	"time"
+
 	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type ProfileBson struct {
-	ID                string              `bson:"id"`
-	Name              string              `bson:"name"`
-	Avatar            string              `bson:"avatar"`
-	HardSkills        []profile.HardSkill `bson:"hardSkills"`
-	SoftSkills        []profile.SoftSkill `bson:"softSkills"`
-	Role              profile.Role        `bson:"role"`
-	JoinTimestamp     int64               `bson:"joinTimestamp"`
-	SelectionPriority int                 `bson:"selectionPriority"`
+	ID                string                                `bson:"id"`
+	Name              string                                `bson:"name"`
+	Avatar            string                                `bson:"avatar"`
+	HardSkills        []profile.HardSkill                   `bson:"hardSkills"`
+	SoftSkills        []profile.SoftSkill                   `bson:"softSkills"`
+	HardSkillLevels   map[profile.HardSkill]ProficiencyBson `bson:"hardSkillLevels"` // empty when stored before skill levels existed
+	SoftSkillLevels   map[profile.SoftSkill]ProficiencyBson `bson:"softSkillLevels"`
+	Role              profile.Role                          `bson:"role"`
+	JoinTimestamp     int64                                 `bson:"joinTimestamp"`
+	SelectionPriority int                                   `bson:"selectionPriority"`
+}
+
+type ProficiencyBson struct {
+	Level profile.SkillLevel `bson:"level"`
+	Notes string             `bson:"notes"`
 }
 
 type TeamBson struct {
@@ -24,25 +34,115 @@ type TeamBson struct {
 }
 
 type LobbyBson struct {
-	ID            string             `bson:"_id"`
-	AccessCode    string             `bson:"accessCode"`
-	Master        ProfileBson        `bson:"master"`
-	Name          string             `bson:"name"`
-	MaxHardSkills int                `bson:"maxHardSkills"`
-	MaxSoftSkills int                `bson:"maxSoftSkills"`
-	Players       []ProfileBson      `bson:"players"`
-	Mentors       []ProfileBson      `bson:"mentors"`
-	Teams         []*TeamBson        `bson:"teams"`
-	Status        lobby_.LobbyStatus `bson:"status"`
-	ChooseControl *ChooseControlBson `bson:"chooseControl"`
+	ID                string                       `bson:"_id"`
+	AccessCode        string                       `bson:"accessCode"`
+	Master            ProfileBson                  `bson:"master"`
+	Name              string                       `bson:"name"`
+	MaxHardSkills     int                          `bson:"maxHardSkills"`
+	MaxSoftSkills     int                          `bson:"maxSoftSkills"`
+	MaxPlayers        int                          `bson:"maxPlayers"`
+	MaxMentors        int                          `bson:"maxMentors"`
+	MinPlayersPerTeam int                          `bson:"minPlayersPerTeam"`
+	MaxPlayersPerTeam int                          `bson:"maxPlayersPerTeam"`
+	Players           []ProfileBson                `bson:"players"`
+	Mentors           []ProfileBson                `bson:"mentors"`
+	Departed          []ProfileBson                `bson:"departed"`
+	Kicked            []string                     `bson:"kicked"`
+	Waitlist          []ProfileBson                `bson:"waitlist"`
+	Teams             []*TeamBson                  `bson:"teams"`
+	Status            lobby_.LobbyStatus           `bson:"status"`
+	StatusTimestamps  map[lobby_.LobbyStatus]int64 `bson:"statusTimestamps"`
+	ChooseControl     *ChooseControlBson           `bson:"chooseControl"`
+	DraftHistory      []DraftActionBson            `bson:"draftHistory"`
+	TurnTimeout       time.Duration                `bson:"turnTimeout"`
+	DraftOrder        lobby_.DraftOrder            `bson:"draftOrder"`
+	TeamFormation     lobby_.TeamFormation         `bson:"teamFormation"`
+	PriorityStrategy  PriorityStrategyBson         `bson:"priorityStrategy"`
+	LeaderRule        LeaderRuleBson               `bson:"leaderRule"`
+	SkillCatalogue    SkillCatalogueBson           `bson:"skillCatalogue"` // empty when stored before skill catalogues existed
+	CreatedAt         int64                        `bson:"createdAt"`      // 0 when stored before schema version 2
+	Version           int                          `bson:"version"`
+	SchemaVersion     int                          `bson:"schemaVersion"` // see LobbySchemaVersion
+	Outbox            []LobbyEventBson             `bson:"outbox,omitempty"`
 }
 
 type ChooseControlBson struct {
 	ChoosingNow ProfileBson       `bson:"choosingNow"`
 	Type        lobby_.ChooseType `bson:"type"`
+	Deadline    int64             `bson:"deadline"`
+}
+
+type PriorityStrategyBson struct {
+	Name             lobby_.PriorityStrategyName `bson:"name"`
+	HardSkillWeights map[profile.HardSkill]int   `bson:"hardSkillWeights"`
+	SoftSkillWeights map[profile.SoftSkill]int   `bson:"softSkillWeights"`
+	Seed             int64                       `bson:"seed"`
+}
+
+type LeaderRuleBson struct {
+	Mode       lobby_.LeaderRuleMode `bson:"mode"` // empty when stored before leader rules existed
+	HardSkills []profile.HardSkill   `bson:"hardSkills"`
+	SoftSkills []profile.SoftSkill   `bson:"softSkills"`
+}
+
+type SkillCatalogueBson struct {
+	HardSkills []SkillDefinitionBson `bson:"hardSkills"`
+	SoftSkills []SkillDefinitionBson `bson:"softSkills"`
+}
+
+type SkillDefinitionBson struct {
+	ID       string `bson:"id"`
+	Name     string `bson:"name"`
+	Category string `bson:"category"`
+	Weight   int    `bson:"weight"`
+}
+
+func (c SkillCatalogueBson) ToSkillCatalogue() lobby_.SkillCatalogue {
+	catalogue := lobby_.SkillCatalogue{
+		HardSkills: make([]lobby_.SkillDefinition, len(c.HardSkills)),
+		SoftSkills: make([]lobby_.SkillDefinition, len(c.SoftSkills)),
+	}
+
+	for i, skill := range c.HardSkills {
+		catalogue.HardSkills[i] = lobby_.SkillDefinition(skill)
+	}
+
+	for i, skill := range c.SoftSkills {
+		catalogue.SoftSkills[i] = lobby_.SkillDefinition(skill)
+	}
+
+	return catalogue
 }
 
-func (l *LobbyBson) ToLobby() *lobby_.Lobby {
+func NewSkillCatalogueBson(c lobby_.SkillCatalogue) SkillCatalogueBson {
+	catalogue := SkillCatalogueBson{
+		HardSkills: make([]SkillDefinitionBson, len(c.HardSkills)),
+		SoftSkills: make([]SkillDefinitionBson, len(c.SoftSkills)),
+	}
+
+	for i, skill := range c.HardSkills {
+		catalogue.HardSkills[i] = SkillDefinitionBson(skill)
+	}
+
+	for i, skill := range c.SoftSkills {
+		catalogue.SoftSkills[i] = SkillDefinitionBson(skill)
+	}
+
+	return catalogue
+}
+
+type DraftActionBson struct {
+	Type          lobby_.ChooseType  `bson:"type"`
+	Phase         lobby_.LobbyStatus `bson:"phase"`
+	TeamID        int                `bson:"teamId"`
+	Leader        ProfileBson        `bson:"leader"`
+	Player        ProfileBson        `bson:"player"`
+	Deadline      int64              `bson:"deadline"`
+	Timestamp     int64              `bson:"timestamp"`
+	TurnStartedAt int64              `bson:"turnStartedAt"` // 0 when stored before turn starts were recorded
+}
+
+func (l *LobbyBson) ToLobby() (*lobby_.Lobby, error) {
 	lobby := &lobby_.Lobby{
 		ID:            l.ID,
 		AccessCode:    l.AccessCode,
@@ -50,12 +150,58 @@ func (l *LobbyBson) ToLobby() *lobby_.Lobby {
 		Name:          l.Name,
 		MaxHardSkills: l.MaxHardSkills,
 		MaxSoftSkills: l.MaxSoftSkills,
-		Players:       make([]profile.Profile, len(l.Players)),
-		Mentors:       make([]profile.Profile, len(l.Mentors)),
-		Teams:         make([]*lobby_.Team, len(l.Teams)),
-		Status:        l.Status,
+		Capacity: lobby_.Capacity{
+			MaxPlayers:        l.MaxPlayers,
+			MaxMentors:        l.MaxMentors,
+			MinPlayersPerTeam: l.MinPlayersPerTeam,
+			MaxPlayersPerTeam: l.MaxPlayersPerTeam,
+		},
+		Players:          make([]profile.Profile, len(l.Players)),
+		Mentors:          make([]profile.Profile, len(l.Mentors)),
+		Departed:         make([]profile.Profile, len(l.Departed)),
+		Kicked:           l.Kicked,
+		Waitlist:         make([]profile.Profile, len(l.Waitlist)),
+		Teams:            make([]*lobby_.Team, len(l.Teams)),
+		DraftHistory:     make([]lobby_.DraftAction, len(l.DraftHistory)),
+		Status:           l.Status,
+		StatusTimestamps: l.StatusTimestamps,
+		TurnTimeout:      l.TurnTimeout,
+		DraftOrder:       l.DraftOrder,
+		TeamFormation:    l.TeamFormation,
+		LeaderRule: lobby_.LeaderRule{
+			Mode:       l.LeaderRule.Mode,
+			HardSkills: l.LeaderRule.HardSkills,
+			SoftSkills: l.LeaderRule.SoftSkills,
+		},
+		SkillCatalogue: l.SkillCatalogue.ToSkillCatalogue(),
+		CreatedAt:      l.CreatedAt,
+		Version:        l.Version,
+	}
+
+	if lobby.CreatedAt == 0 { // stored before schema version 2, when the lobby last entered Waiting is the closest to its creation
+		lobby.CreatedAt = l.StatusTimestamps[lobby_.Waiting]
+	}
+
+	if lobby.DraftOrder == "" { // stored before draft orders existed
+		lobby.DraftOrder = lobby_.RoundRobin
 	}
 
+	if lobby.TeamFormation == "" { // stored before balanced teams existed
+		lobby.TeamFormation = lobby_.DraftFormation
+	}
+
+	strategy, err := lobby_.PrioritySettings{ // an empty name, stored before priority strategies existed, is the default
+		Name:             l.PriorityStrategy.Name,
+		HardSkillWeights: l.PriorityStrategy.HardSkillWeights,
+		SoftSkillWeights: l.PriorityStrategy.SoftSkillWeights,
+		Seed:             l.PriorityStrategy.Seed,
+	}.Strategy()
+
+	if err != nil {
+		strategy = lobby_.DefaultPriority{}
+	}
+	lobby.PriorityStrategy = strategy
+
 	for i, player := range l.Players {
 		lobby.Players[i] = player.ToProfile()
 	}
@@ -64,31 +210,93 @@ func (l *LobbyBson) ToLobby() *lobby_.Lobby {
 		lobby.Mentors[i] = mentor.ToProfile()
 	}
 
+	for i, p := range l.Departed {
+		lobby.Departed[i] = p.ToProfile()
+	}
+
+	for i, p := range l.Waitlist {
+		lobby.Waitlist[i] = p.ToProfile()
+	}
+
 	for i, team := range l.Teams {
 		lobby.Teams[i] = team.ToTeam()
 	}
 
+	for i, action := range l.DraftHistory {
+		lobby.DraftHistory[i] = lobby_.DraftAction{
+			Type:          action.Type,
+			Phase:         action.Phase,
+			TeamID:        action.TeamID,
+			Leader:        action.Leader.ToProfile(),
+			Player:        action.Player.ToProfile(),
+			Deadline:      action.Deadline,
+			Timestamp:     action.Timestamp,
+			TurnStartedAt: action.TurnStartedAt,
+		}
+	}
+
 	if l.ChooseControl != nil {
 		lobby.ChooseControl = &lobby_.ChooseControl{
 			ChoosingNow: l.ChooseControl.ChoosingNow.ToProfile(),
 			Type:        l.ChooseControl.Type,
+			Deadline:    l.ChooseControl.Deadline,
+		}
+	}
+
+	for _, document := range l.Outbox {
+		event, err := document.ToLobbyEvent()
+		if err != nil {
+			return nil, err
 		}
+
+		lobby.Outbox = append(lobby.Outbox, event)
 	}
 
-	return lobby
+	return lobby, nil
 }
 
 func (p *ProfileBson) ToProfile() profile.Profile {
-	return profile.Profile{
+	result := profile.Profile{
 		ID:                p.ID,
 		Name:              p.Name,
 		Avatar:            p.Avatar,
 		HardSkills:        p.HardSkills,
 		SoftSkills:        p.SoftSkills,
+		HardSkillLevels:   toProficiencies(p.HardSkillLevels),
+		SoftSkillLevels:   toProficiencies(p.SoftSkillLevels),
 		Role:              p.Role,
 		JoinTimestamp:     p.JoinTimestamp,
 		SelectionPriority: p.SelectionPriority,
 	}
+
+	result.FillSkillLevels() // stored before skill levels existed
+	return result
+}
+
+func toProficiencies[S profile.HardSkill | profile.SoftSkill](documents map[S]ProficiencyBson) map[S]profile.Proficiency {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	levels := make(map[S]profile.Proficiency, len(documents))
+	for skill, document := range documents {
+		levels[skill] = profile.Proficiency(document)
+	}
+
+	return levels
+}
+
+func newProficienciesBson[S profile.HardSkill | profile.SoftSkill](levels map[S]profile.Proficiency) map[S]ProficiencyBson {
+	if len(levels) == 0 {
+		return nil
+	}
+
+	documents := make(map[S]ProficiencyBson, len(levels))
+	for skill, proficiency := range levels {
+		documents[skill] = ProficiencyBson(proficiency)
+	}
+
+	return documents
 }
 
 func (t *TeamBson) ToTeam() *lobby_.Team {
@@ -113,6 +321,8 @@ func NewProfileBson(p profile.Profile) ProfileBson {
 		Avatar:            p.Avatar,
 		HardSkills:        p.HardSkills,
 		SoftSkills:        p.SoftSkills,
+		HardSkillLevels:   newProficienciesBson(p.HardSkillLevels),
+		SoftSkillLevels:   newProficienciesBson(p.SoftSkillLevels),
 		Role:              p.Role,
 		JoinTimestamp:     p.JoinTimestamp,
 		SelectionPriority: p.SelectionPriority,
@@ -134,18 +344,54 @@ func NewTeamBson(t *lobby_.Team) *TeamBson {
 	return team
 }
 
-func NewLobbyBson(l *lobby_.Lobby) LobbyBson {
+func NewPriorityStrategyBson(strategy lobby_.PriorityStrategy) PriorityStrategyBson {
+	if strategy == nil {
+		strategy = lobby_.DefaultPriority{}
+	}
+
+	settings := strategy.Settings()
+	return PriorityStrategyBson{
+		Name:             settings.Name,
+		HardSkillWeights: settings.HardSkillWeights,
+		SoftSkillWeights: settings.SoftSkillWeights,
+		Seed:             settings.Seed,
+	}
+}
+
+func NewLobbyBson(l *lobby_.Lobby) (LobbyBson, error) {
 	lobby := LobbyBson{
-		ID:            l.ID,
-		AccessCode:    l.AccessCode,
-		Master:        NewProfileBson(l.Master),
-		Name:          l.Name,
-		MaxHardSkills: l.MaxHardSkills,
-		MaxSoftSkills: l.MaxSoftSkills,
-		Players:       make([]ProfileBson, len(l.Players)),
-		Mentors:       make([]ProfileBson, len(l.Mentors)),
-		Teams:         make([]*TeamBson, len(l.Teams)),
-		Status:        l.Status,
+		ID:                l.ID,
+		AccessCode:        l.AccessCode,
+		Master:            NewProfileBson(l.Master),
+		Name:              l.Name,
+		MaxHardSkills:     l.MaxHardSkills,
+		MaxSoftSkills:     l.MaxSoftSkills,
+		MaxPlayers:        l.MaxPlayers,
+		MaxMentors:        l.MaxMentors,
+		MinPlayersPerTeam: l.MinPlayersPerTeam,
+		MaxPlayersPerTeam: l.MaxPlayersPerTeam,
+		Players:           make([]ProfileBson, len(l.Players)),
+		Mentors:           make([]ProfileBson, len(l.Mentors)),
+		Departed:          make([]ProfileBson, len(l.Departed)),
+		Kicked:            l.Kicked,
+		Waitlist:          make([]ProfileBson, len(l.Waitlist)),
+		Teams:             make([]*TeamBson, len(l.Teams)),
+		DraftHistory:      make([]DraftActionBson, len(l.DraftHistory)),
+		Status:            l.Status,
+		StatusTimestamps:  l.StatusTimestamps,
+		TurnTimeout:       l.TurnTimeout,
+		DraftOrder:        l.DraftOrder,
+		TeamFormation:     l.TeamFormation,
+		PriorityStrategy:  NewPriorityStrategyBson(l.PriorityStrategy),
+		LeaderRule: LeaderRuleBson{
+			Mode:       l.LeaderRule.Mode,
+			HardSkills: l.LeaderRule.HardSkills,
+			SoftSkills: l.LeaderRule.SoftSkills,
+		},
+		SkillCatalogue: NewSkillCatalogueBson(l.SkillCatalogue),
+		CreatedAt:      l.CreatedAt,
+		Version:        l.Version,
+		SchemaVersion:  LobbySchemaVersion,
 	}
 
 	for i, player := range l.Players {
@@ -156,16 +402,96 @@ func NewLobbyBson(l *lobby_.Lobby) LobbyBson {
 		lobby.Mentors[i] = NewProfileBson(mentor)
 	}
 
+	for i, p := range l.Departed {
+		lobby.Departed[i] = NewProfileBson(p)
+	}
+
+	for i, p := range l.Waitlist {
+		lobby.Waitlist[i] = NewProfileBson(p)
+	}
+
 	for i, team := range l.Teams {
 		lobby.Teams[i] = NewTeamBson(team)
 	}
 
+	for i, action := range l.DraftHistory {
+		lobby.DraftHistory[i] = DraftActionBson{
+			Type:          action.Type,
+			Phase:         action.Phase,
+			TeamID:        action.TeamID,
+			Leader:        NewProfileBson(action.Leader),
+			Player:        NewProfileBson(action.Player),
+			Deadline:      action.Deadline,
+			Timestamp:     action.Timestamp,
+			TurnStartedAt: action.TurnStartedAt,
+		}
+	}
+
 	if l.ChooseControl != nil {
 		lobby.ChooseControl = &ChooseControlBson{
 			ChoosingNow: NewProfileBson(l.ChooseControl.ChoosingNow),
 			Type:        l.ChooseControl.Type,
+			Deadline:    l.ChooseControl.Deadline,
+		}
+	}
+
+	for _, event := range l.Outbox {
+		document, err := NewLobbyEventBson(event)
+		if err != nil {
+			return LobbyBson{}, err
 		}
+
+		lobby.Outbox = append(lobby.Outbox, document)
+	}
+
+	return lobby, nil
+}
+
+// LobbyEventBson is a stored lobby event. Index orders the events produced
+// by the same update, which share the version.
+type LobbyEventBson struct {
+	AccessCode string                `bson:"accessCode"`
+	Version    int                   `bson:"version"`
+	Index      int                   `bson:"index"`
+	Type       lobby_.LobbyEventType `bson:"type"`
+	Actor      string                `bson:"actor"`
+	Timestamp  int64                 `bson:"timestamp"`
+	Payload    bson.Raw              `bson:"payload"`
+}
+
+func (e *LobbyEventBson) ToLobbyEvent() (lobby_.LobbyEvent, error) {
+	payload, err := lobby_.DecodeEventPayload(e.Type, func(v interface{}) error {
+		return bson.Unmarshal(e.Payload, v)
+	})
+
+	if err != nil {
+		return lobby_.LobbyEvent{}, err
+	}
+
+	return lobby_.LobbyEvent{
+		Type:       e.Type,
+		AccessCode: e.AccessCode,
+		Actor:      e.Actor,
+		Version:    e.Version,
+		Index:      e.Index,
+		Timestamp:  e.Timestamp,
+		Payload:    payload,
+	}, nil
+}
+
+func NewLobbyEventBson(e lobby_.LobbyEvent) (LobbyEventBson, error) {
+	payload, err := bson.Marshal(e.Payload)
+	if err != nil {
+		return LobbyEventBson{}, err
 	}
 
-	return lobby
+	return LobbyEventBson{
+		AccessCode: e.AccessCode,
+		Version:    e.Version,
+		Index:      e.Index,
+		Type:       e.Type,
+		Actor:      e.Actor,
+		Timestamp:  e.Timestamp,
+		Payload:    payload,
+	}, nil
 }
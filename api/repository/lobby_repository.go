@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"regexp"
 
 	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,8 +20,19 @@ func NewMongoLobbyRepository(db *mongo.Database, collectionName string) *MongoLo
 	}
 }
 
+// Save returns ErrAccessCodeTaken when another lobby has the access code,
+// which the index created by EnsureIndexes guarantees.
 func (r *MongoLobbyRepository) Save(ctx context.Context, lobby *lobby_.Lobby) error {
-	_, err := r.collection.InsertOne(ctx, NewLobbyBson(lobby))
+	document, err := NewLobbyBson(lobby)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.InsertOne(ctx, document)
+	if mongo.IsDuplicateKeyError(err) {
+		return lobby_.ErrAccessCodeTaken.WithDetails(map[string]interface{}{"access_code": lobby.AccessCode})
+	}
+
 	return err
 }
 
@@ -32,19 +43,151 @@ func (r *MongoLobbyRepository) FindByAccessCode(ctx context.Context, accessCode
 	err := r.collection.FindOne(ctx, filter).Decode(&lobby)
 
 	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("lobby_not_found")
+		return nil, lobby_.ErrLobbyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lobby.ToLobby()
+}
+
+func (r *MongoLobbyRepository) FindWithTurnDeadline(ctx context.Context) ([]*lobby_.Lobby, error) {
+	filter := bson.M{"chooseControl.deadline": bson.M{"$gt": 0}}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []LobbyBson
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	return toLobbies(documents)
+}
+
+func (r *MongoLobbyRepository) List(ctx context.Context, filter lobby_.LobbyFilter, page lobby_.PageRequest) (lobby_.LobbyPage, error) {
+	after, err := page.After()
+	if err != nil {
+		return lobby_.LobbyPage{}, err
+	}
+
+	query := listFilter(filter)
+	if after != nil {
+		query["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": after.CreatedAt}},
+			bson.M{"createdAt": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(page.Size() + 1))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return lobby_.LobbyPage{}, err
+	}
+
+	var documents []LobbyBson
+	if err := cursor.All(ctx, &documents); err != nil {
+		return lobby_.LobbyPage{}, err
+	}
+
+	lobbies, err := toLobbies(documents)
+	if err != nil {
+		return lobby_.LobbyPage{}, err
+	}
+
+	return lobby_.NewLobbyPage(lobbies, page), nil
+}
+
+// listFilter matches the documents LobbyFilter.Matches matches.
+func listFilter(filter lobby_.LobbyFilter) bson.M {
+	query := bson.M{}
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	if filter.MasterID != "" {
+		query["master.id"] = filter.MasterID
+	}
+
+	if filter.Name != "" {
+		query["name"] = bson.M{"$regex": regexp.QuoteMeta(filter.Name), "$options": "i"}
+	}
+
+	created := bson.M{}
+	if filter.CreatedFrom != 0 {
+		created["$gte"] = filter.CreatedFrom
 	}
-	return lobby.ToLobby(), err
+
+	if filter.CreatedTo != 0 {
+		created["$lt"] = filter.CreatedTo
+	}
+
+	if len(created) > 0 {
+		query["createdAt"] = created
+	}
+
+	return query
 }
 
 func (r *MongoLobbyRepository) Update(ctx context.Context, lobby *lobby_.Lobby) error {
-	filter := bson.M{"_id": lobby.ID}
+	filter := versionFilter(lobby.ID, lobby.Version)
+
+	document, err := NewLobbyBson(lobby)
+	if err != nil {
+		return err
+	}
+	document.Version = lobby.Version + 1
 
 	update := bson.M{
-		"$set": NewLobbyBson(lobby),
+		"$set": document,
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return &lobby_.VersionConflictError{LobbyID: lobby.ID, Version: lobby.Version}
+	}
+
+	lobby.Version = document.Version
+	return nil
+}
+
+func toLobbies(documents []LobbyBson) ([]*lobby_.Lobby, error) {
+	lobbies := make([]*lobby_.Lobby, len(documents))
+	for i, document := range documents {
+		lobby, err := document.ToLobby()
+		if err != nil {
+			return nil, err
+		}
+
+		lobbies[i] = lobby
+	}
+
+	return lobbies, nil
+}
+
+// versionFilter matches the lobby only while it is still at version.
+func versionFilter(id string, version int) bson.M {
+	if version == 0 { // documents stored before versioning have no version field
+		return bson.M{
+			"_id": id,
+			"$or": bson.A{
+				bson.M{"version": 0},
+				bson.M{"version": bson.M{"$exists": false}},
+			},
+		}
+	}
+
+	return bson.M{"_id": id, "version": version}
 }
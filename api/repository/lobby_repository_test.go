@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/lobby/lobbytest"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestMongoLobbyRepository runs against the MongoDB at PAQ_TEST_MONGO_URI,
+// each test in a database of its own that is dropped afterwards.
+func TestMongoLobbyRepository(t *testing.T) {
+	uri := os.Getenv("PAQ_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("PAQ_TEST_MONGO_URI is not set")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	lobbytest.Run(t, func(t *testing.T) lobby_.LobbyRepository {
+		db := client.Database("paq_test_" + strings.ReplaceAll(uuid.New().String(), "-", ""))
+		t.Cleanup(func() { db.Drop(context.Background()) })
+
+		return NewMongoLobbyRepository(db, "lobby")
+	})
+}
+
+func TestMemoryLobbyRepository(t *testing.T) {
+	lobbytest.Run(t, func(t *testing.T) lobby_.LobbyRepository {
+		return NewMemoryLobbyRepository()
+	})
+}
+
+func TestSQLLobbyRepository(t *testing.T) {
+	lobbytest.Run(t, func(t *testing.T) lobby_.LobbyRepository {
+		return NewSQLLobbyRepository(openTestSQLite(t), SQLite)
+	})
+}
+
+// openTestSQLite returns a migrated SQLite database that is removed after the
+// test.
+func openTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "paq.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := MigrateSQL(context.Background(), db, SQLite); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return db
+}
+
+// TestPostgresLobbyRepository runs against the PostgreSQL at
+// PAQ_TEST_POSTGRES_DSN, each test in a schema of its own that is dropped
+// afterwards.
+func TestPostgresLobbyRepository(t *testing.T) {
+	dsn := testPostgresDSN(t)
+
+	lobbytest.Run(t, func(t *testing.T) lobby_.LobbyRepository {
+		return NewSQLLobbyRepository(openTestPostgres(t, dsn), Postgres)
+	})
+}
+
+// testPostgresDSN returns PAQ_TEST_POSTGRES_DSN, skipping the test when it is
+// not set.
+func testPostgresDSN(t *testing.T) string {
+	t.Helper()
+
+	dsn := os.Getenv("PAQ_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("PAQ_TEST_POSTGRES_DSN is not set")
+	}
+
+	return dsn
+}
+
+// openTestPostgres returns a database migrated in a schema of its own, which
+// is dropped after the test.
+func openTestPostgres(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+
+	admin, err := OpenPostgres(dsn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := "paq_test_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config.RuntimeParams["search_path"] = schema
+
+	db := stdlib.OpenDB(*config)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := MigrateSQL(context.Background(), db, Postgres); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return db
+}
+
+// TestSQLLobbyRepository_PositionGaps reads lists whose positions do not
+// start at 0 nor follow each other, as rows written by hand may be.
+func TestSQLLobbyRepository_PositionGaps(t *testing.T) {
+	ctx := context.Background()
+	db := openTestSQLite(t)
+	repository := NewSQLLobbyRepository(db, SQLite)
+
+	lobby := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Gaps", 2, 2)
+	for _, name := range []string{"Player 1", "Player 2", "Player 3"} {
+		p := profile.NewPlayer(name, "avatar", []profile.HardSkill{profile.IA}, []profile.SoftSkill{profile.Empathy})
+		if err := lobby.Join(p); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if err := repository.Save(ctx, lobby); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	statements := []string{
+		`PRAGMA foreign_keys = OFF`,
+		`UPDATE lobby_profiles SET position = position * 10 + 5`,
+		`UPDATE lobby_profile_skills SET position = position * 10 + 5`,
+		`PRAGMA foreign_keys = ON`,
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	found, err := repository.FindByAccessCode(ctx, lobby.AccessCode)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !found.SameState(lobby) {
+		t.Errorf("Expected the stored lobby to be %+v, got %+v", lobby_.ResponseFromLobby(lobby), lobby_.ResponseFromLobby(found))
+	}
+}
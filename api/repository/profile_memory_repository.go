@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// MemoryProfileRepository keeps the identities in memory, for local
+// development and tests. It stores and returns copies, so callers never share
+// them.
+type MemoryProfileRepository struct {
+	mu         sync.RWMutex
+	identities map[string]profile.Identity
+}
+
+func NewMemoryProfileRepository() *MemoryProfileRepository {
+	return &MemoryProfileRepository{
+		identities: make(map[string]profile.Identity),
+	}
+}
+
+func (r *MemoryProfileRepository) Save(ctx context.Context, identity profile.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.identities[identity.ID] = copyIdentity(identity)
+	return nil
+}
+
+func (r *MemoryProfileRepository) FindByID(ctx context.Context, id string) (profile.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identity, ok := r.identities[id]
+	if !ok {
+		return profile.Identity{}, lobby_.ErrProfileNotFound
+	}
+
+	return copyIdentity(identity), nil
+}
+
+func (r *MemoryProfileRepository) Update(ctx context.Context, identity profile.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.identities[identity.ID]; !ok {
+		return lobby_.ErrProfileNotFound
+	}
+
+	r.identities[identity.ID] = copyIdentity(identity)
+	return nil
+}
+
+func copyIdentity(identity profile.Identity) profile.Identity {
+	copied := identity
+	copied.HardSkills = append([]profile.HardSkill(nil), identity.HardSkills...)
+	copied.SoftSkills = append([]profile.SoftSkill(nil), identity.SoftSkills...)
+	copied.HardSkillLevels = copyLevels(identity.HardSkillLevels)
+	copied.SoftSkillLevels = copyLevels(identity.SoftSkillLevels)
+	return copied
+}
+
+func copyLevels[S profile.HardSkill | profile.SoftSkill](levels map[S]profile.Proficiency) map[S]profile.Proficiency {
+	if levels == nil {
+		return nil
+	}
+
+	copied := make(map[S]profile.Proficiency, len(levels))
+	for skill, proficiency := range levels {
+		copied[skill] = proficiency
+	}
+
+	return copied
+}
@@ -0,0 +1,46 @@
+package repository
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+type IdentityBson struct {
+	ID              string                                `bson:"_id"`
+	Name            string                                `bson:"name"`
+	Avatar          string                                `bson:"avatar"`
+	HardSkills      []profile.HardSkill                   `bson:"hardSkills"`
+	SoftSkills      []profile.SoftSkill                   `bson:"softSkills"`
+	HardSkillLevels map[profile.HardSkill]ProficiencyBson `bson:"hardSkillLevels"`
+	SoftSkillLevels map[profile.SoftSkill]ProficiencyBson `bson:"softSkillLevels"`
+	CreatedAt       int64                                 `bson:"createdAt"`
+	UpdatedAt       int64                                 `bson:"updatedAt"`
+	SecretHash      string                                `bson:"secretHash"` // empty when stored before profiles had secrets
+}
+
+func (i *IdentityBson) ToIdentity() profile.Identity {
+	return profile.Identity{
+		ID:              i.ID,
+		Name:            i.Name,
+		Avatar:          i.Avatar,
+		HardSkills:      i.HardSkills,
+		SoftSkills:      i.SoftSkills,
+		HardSkillLevels: toProficiencies(i.HardSkillLevels),
+		SoftSkillLevels: toProficiencies(i.SoftSkillLevels),
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
+		SecretHash:      i.SecretHash,
+	}
+}
+
+func NewIdentityBson(i profile.Identity) IdentityBson {
+	return IdentityBson{
+		ID:              i.ID,
+		Name:            i.Name,
+		Avatar:          i.Avatar,
+		HardSkills:      i.HardSkills,
+		SoftSkills:      i.SoftSkills,
+		HardSkillLevels: newProficienciesBson(i.HardSkillLevels),
+		SoftSkillLevels: newProficienciesBson(i.SoftSkillLevels),
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
+		SecretHash:      i.SecretHash,
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoProfileRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProfileRepository(db *mongo.Database, collectionName string) *MongoProfileRepository {
+	return &MongoProfileRepository{
+		collection: db.Collection(collectionName),
+	}
+}
+
+func (r *MongoProfileRepository) Save(ctx context.Context, identity profile.Identity) error {
+	_, err := r.collection.InsertOne(ctx, NewIdentityBson(identity))
+	return err
+}
+
+func (r *MongoProfileRepository) FindByID(ctx context.Context, id string) (profile.Identity, error) {
+	var identity IdentityBson
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&identity)
+	if err == mongo.ErrNoDocuments {
+		return profile.Identity{}, lobby_.ErrProfileNotFound
+	}
+
+	if err != nil {
+		return profile.Identity{}, err
+	}
+
+	return identity.ToIdentity(), nil
+}
+
+func (r *MongoProfileRepository) Update(ctx context.Context, identity profile.Identity) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": identity.ID}, NewIdentityBson(identity))
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return lobby_.ErrProfileNotFound
+	}
+
+	return nil
+}
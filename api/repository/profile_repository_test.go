@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func newIdentity(name string) profile.Identity {
+	p := profile.NewPlayer(name, "avatar", []profile.HardSkill{profile.Programming, profile.Design}, []profile.SoftSkill{profile.Empathy})
+	p.FillSkillLevels()
+	return p.Identity()
+}
+
+func TestMemoryProfileRepository(t *testing.T) {
+	testProfileRepository(t, NewMemoryProfileRepository())
+}
+
+func TestSQLProfileRepository(t *testing.T) {
+	testProfileRepository(t, NewSQLProfileRepository(openTestSQLite(t), SQLite))
+}
+
+func TestPostgresProfileRepository(t *testing.T) {
+	testProfileRepository(t, NewSQLProfileRepository(openTestPostgres(t, testPostgresDSN(t)), Postgres))
+}
+
+func testProfileRepository(t *testing.T, repo lobby_.ProfileRepository) {
+	ctx := context.Background()
+
+	identity := newIdentity("Ana")
+	identity.HardSkillLevels[profile.Programming] = profile.Proficiency{Level: 5, Notes: "ships every week"}
+	identity.CreatedAt = 100
+	identity.SecretHash = "hash"
+
+	if err := repo.Save(ctx, identity); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, identity.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if found.Name != "Ana" || found.CreatedAt != 100 || found.SecretHash != "hash" || len(found.HardSkills) != 2 ||
+		found.HardSkillLevels[profile.Programming].Notes != "ships every week" || found.SoftSkillLevels[profile.Empathy].Level != profile.DefaultSkillLevel {
+		t.Errorf("Expected the stored identity, got %+v", found)
+	}
+
+	found.Name = "Ana Maria"
+	found.SoftSkills = nil
+	found.SoftSkillLevels = nil
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updated, _ := repo.FindByID(ctx, identity.ID)
+	if updated.Name != "Ana Maria" || len(updated.SoftSkills) != 0 || len(updated.SoftSkillLevels) != 0 {
+		t.Errorf("Expected the updated identity, got %+v", updated)
+	}
+
+	if _, err := repo.FindByID(ctx, "missing"); !errors.Is(err, lobby_.ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+
+	if err := repo.Update(ctx, newIdentity("Missing")); !errors.Is(err, lobby_.ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestMemoryProfileRepository_ReturnsCopies(t *testing.T) {
+	repo := NewMemoryProfileRepository()
+	identity := newIdentity("Ana")
+	_ = repo.Save(context.Background(), identity)
+
+	identity.HardSkills[0] = profile.Marketing
+	found, _ := repo.FindByID(context.Background(), identity.ID)
+	found.HardSkillLevels[profile.Programming] = profile.Proficiency{Level: 1}
+
+	stored, _ := repo.FindByID(context.Background(), identity.ID)
+	if stored.HardSkills[0] != profile.Programming || stored.HardSkillLevels[profile.Programming].Level != profile.DefaultSkillLevel {
+		t.Errorf("Expected the stored identity not to change, got %+v", stored)
+	}
+}
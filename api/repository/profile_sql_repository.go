@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// SQLProfileRepository stores the identities in the identities table, with
+// the skills and their levels as JSON.
+type SQLProfileRepository struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLProfileRepository expects a database already migrated with MigrateSQL.
+func NewSQLProfileRepository(db *sql.DB, dialect SQLDialect) *SQLProfileRepository {
+	return &SQLProfileRepository{db: db, dialect: dialect}
+}
+
+func (r *SQLProfileRepository) Save(ctx context.Context, identity profile.Identity) error {
+	skills, err := identitySkills(identity)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.dialect.rebind(`INSERT INTO identities (
+		id, name, avatar, hard_skills, soft_skills, hard_skill_levels, soft_skill_levels, created_at, updated_at, secret_hash
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		identity.ID, identity.Name, identity.Avatar, skills[0], skills[1], skills[2], skills[3],
+		identity.CreatedAt, identity.UpdatedAt, identity.SecretHash)
+	return err
+}
+
+func (r *SQLProfileRepository) FindByID(ctx context.Context, id string) (profile.Identity, error) {
+	identity := profile.Identity{ID: id}
+	var skills [4]string
+
+	err := r.db.QueryRowContext(ctx, r.dialect.rebind(`SELECT
+		name, avatar, hard_skills, soft_skills, hard_skill_levels, soft_skill_levels, created_at, updated_at, secret_hash
+	FROM identities WHERE id = ?`), id).Scan(
+		&identity.Name, &identity.Avatar, &skills[0], &skills[1], &skills[2], &skills[3],
+		&identity.CreatedAt, &identity.UpdatedAt, &identity.SecretHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile.Identity{}, lobby_.ErrProfileNotFound
+	}
+
+	if err != nil {
+		return profile.Identity{}, err
+	}
+
+	var hardSkillLevels map[profile.HardSkill]ProficiencyBson
+	var softSkillLevels map[profile.SoftSkill]ProficiencyBson
+
+	targets := []interface{}{&identity.HardSkills, &identity.SoftSkills, &hardSkillLevels, &softSkillLevels}
+	for i, target := range targets {
+		if err := json.Unmarshal([]byte(skills[i]), target); err != nil {
+			return profile.Identity{}, err
+		}
+	}
+
+	identity.HardSkillLevels = toProficiencies(hardSkillLevels)
+	identity.SoftSkillLevels = toProficiencies(softSkillLevels)
+	return identity, nil
+}
+
+func (r *SQLProfileRepository) Update(ctx context.Context, identity profile.Identity) error {
+	skills, err := identitySkills(identity)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind(`UPDATE identities SET
+		name = ?, avatar = ?, hard_skills = ?, soft_skills = ?, hard_skill_levels = ?, soft_skill_levels = ?,
+		created_at = ?, updated_at = ?, secret_hash = ?
+	WHERE id = ?`),
+		identity.Name, identity.Avatar, skills[0], skills[1], skills[2], skills[3],
+		identity.CreatedAt, identity.UpdatedAt, identity.SecretHash, identity.ID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return lobby_.ErrProfileNotFound
+	}
+
+	return nil
+}
+
+// identitySkills encodes the skills and the levels of the identity, the
+// levels as the documents MongoProfileRepository writes.
+func identitySkills(identity profile.Identity) ([4]string, error) {
+	var skills [4]string
+
+	values := []interface{}{
+		identity.HardSkills,
+		identity.SoftSkills,
+		newProficienciesBson(identity.HardSkillLevels),
+		newProficienciesBson(identity.SoftSkillLevels),
+	}
+
+	for i, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return skills, err
+		}
+
+		skills[i] = string(encoded)
+	}
+
+	return skills, nil
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLDialect adapts the queries of SQLLobbyRepository to the database.
+type SQLDialect string
+
+const (
+	SQLite   SQLDialect = "sqlite"
+	Postgres SQLDialect = "postgres"
+)
+
+// rebind turns the ? placeholders of the query into the ones of the dialect.
+func (d SQLDialect) rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	var rebound strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			rebound.WriteRune(r)
+			continue
+		}
+
+		n++
+		rebound.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return rebound.String()
+}
+
+// isUniqueViolation reports whether err is the database rejecting a row that
+// repeats the unique column of the table.
+func (d SQLDialect) isUniqueViolation(err error, table string, column string) bool {
+	if d == Postgres {
+		// the default name of the constraint is <table>_<column>_key
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == table+"_"+column+"_key"
+	}
+
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE && strings.Contains(sqliteErr.Error(), table+"."+column)
+}
+
+type sqlMigration struct {
+	version    int
+	statements []string
+}
+
+// sqlMigrations are applied in order and never changed once released, a
+// change to the schema is a new migration. Types and statements are the
+// ones SQLite and PostgreSQL share.
+var sqlMigrations = []sqlMigration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE lobbies (
+				id                   TEXT PRIMARY KEY,
+				access_code          TEXT NOT NULL UNIQUE,
+				name                 TEXT NOT NULL,
+				max_hard_skills      INTEGER NOT NULL,
+				max_soft_skills      INTEGER NOT NULL,
+				max_players          INTEGER NOT NULL,
+				max_mentors          INTEGER NOT NULL,
+				min_players_per_team INTEGER NOT NULL,
+				max_players_per_team INTEGER NOT NULL,
+				status               TEXT NOT NULL,
+				turn_timeout         BIGINT NOT NULL,
+				draft_order          TEXT NOT NULL,
+				team_formation       TEXT NOT NULL,
+				priority_strategy    TEXT NOT NULL,
+				leader_rule          TEXT NOT NULL,
+				skill_catalogue      TEXT NOT NULL,
+				version              INTEGER NOT NULL
+			)`,
+			// every profile of a lobby: its master, members, teams, current
+			// turn and draft history, in the order of each list
+			`CREATE TABLE lobby_profiles (
+				lobby_id           TEXT NOT NULL REFERENCES lobbies (id),
+				list               TEXT NOT NULL,
+				owner              INTEGER NOT NULL,
+				position           INTEGER NOT NULL,
+				profile_id         TEXT NOT NULL,
+				name               TEXT NOT NULL,
+				avatar             TEXT NOT NULL,
+				role               TEXT NOT NULL,
+				join_timestamp     BIGINT NOT NULL,
+				selection_priority INTEGER NOT NULL,
+				PRIMARY KEY (lobby_id, list, owner, position)
+			)`,
+			`CREATE TABLE lobby_profile_skills (
+				lobby_id       TEXT NOT NULL,
+				list           TEXT NOT NULL,
+				owner          INTEGER NOT NULL,
+				position       INTEGER NOT NULL,
+				kind           TEXT NOT NULL,
+				skill_position INTEGER NOT NULL,
+				skill          TEXT NOT NULL,
+				level          INTEGER,
+				notes          TEXT NOT NULL,
+				PRIMARY KEY (lobby_id, list, owner, position, kind, skill_position),
+				FOREIGN KEY (lobby_id, list, owner, position) REFERENCES lobby_profiles (lobby_id, list, owner, position)
+			)`,
+			`CREATE TABLE teams (
+				lobby_id TEXT NOT NULL REFERENCES lobbies (id),
+				id       INTEGER NOT NULL,
+				position INTEGER NOT NULL,
+				PRIMARY KEY (lobby_id, id)
+			)`,
+			`CREATE TABLE choose_controls (
+				lobby_id TEXT PRIMARY KEY REFERENCES lobbies (id),
+				type     TEXT NOT NULL,
+				deadline BIGINT NOT NULL
+			)`,
+			`CREATE INDEX choose_controls_deadline ON choose_controls (deadline)`,
+			`CREATE TABLE draft_actions (
+				lobby_id TEXT NOT NULL REFERENCES lobbies (id),
+				position INTEGER NOT NULL,
+				type     TEXT NOT NULL,
+				phase    TEXT NOT NULL,
+				team_id  INTEGER NOT NULL,
+				deadline BIGINT NOT NULL,
+				acted_at BIGINT NOT NULL,
+				PRIMARY KEY (lobby_id, position)
+			)`,
+			`CREATE TABLE status_timestamps (
+				lobby_id   TEXT NOT NULL REFERENCES lobbies (id),
+				status     TEXT NOT NULL,
+				entered_at BIGINT NOT NULL,
+				PRIMARY KEY (lobby_id, status)
+			)`,
+			`CREATE TABLE kicked_profiles (
+				lobby_id   TEXT NOT NULL REFERENCES lobbies (id),
+				position   INTEGER NOT NULL,
+				profile_id TEXT NOT NULL,
+				PRIMARY KEY (lobby_id, position)
+			)`,
+		},
+	},
+	{
+		version: 2,
+		statements: []string{
+			`ALTER TABLE lobbies ADD COLUMN created_at BIGINT NOT NULL DEFAULT 0`,
+			// when the lobby last entered Waiting is the closest to the
+			// creation of the lobbies already stored
+			`UPDATE lobbies SET created_at = COALESCE((
+				SELECT entered_at FROM status_timestamps
+				WHERE status_timestamps.lobby_id = lobbies.id AND status_timestamps.status = 'Waiting'
+			), 0)`,
+			`CREATE INDEX lobbies_status_created_at ON lobbies (status, created_at)`,
+			`CREATE INDEX lobbies_created_at ON lobbies (created_at)`,
+		},
+	},
+	{
+		version: 3,
+		statements: []string{
+			`CREATE INDEX lobby_profiles_list_profile_id ON lobby_profiles (list, profile_id)`,
+		},
+	},
+	{
+		version: 4,
+		statements: []string{
+			// the events of the last update not yet appended to the event
+			// log, stored in the same transaction as the lobby
+			`CREATE TABLE lobby_outbox (
+				lobby_id    TEXT NOT NULL REFERENCES lobbies (id),
+				position    INTEGER NOT NULL,
+				version     INTEGER NOT NULL,
+				event_index INTEGER NOT NULL,
+				type        TEXT NOT NULL,
+				actor       TEXT NOT NULL,
+				occurred_at BIGINT NOT NULL,
+				payload     TEXT NOT NULL,
+				PRIMARY KEY (lobby_id, position)
+			)`,
+		},
+	},
+	{
+		version: 5,
+		statements: []string{
+			// 0 for the selections made before turn starts were recorded
+			`ALTER TABLE draft_actions ADD COLUMN turn_started_at BIGINT NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version: 6,
+		statements: []string{
+			`CREATE TABLE lobby_events (
+				access_code TEXT NOT NULL,
+				version     INTEGER NOT NULL,
+				event_index INTEGER NOT NULL,
+				type        TEXT NOT NULL,
+				actor       TEXT NOT NULL,
+				occurred_at BIGINT NOT NULL,
+				payload     TEXT NOT NULL,
+				PRIMARY KEY (access_code, version, event_index)
+			)`,
+			// the skills and levels are only ever read and written whole
+			`CREATE TABLE identities (
+				id                TEXT PRIMARY KEY,
+				name              TEXT NOT NULL,
+				avatar            TEXT NOT NULL,
+				hard_skills       TEXT NOT NULL,
+				soft_skills       TEXT NOT NULL,
+				hard_skill_levels TEXT NOT NULL,
+				soft_skill_levels TEXT NOT NULL,
+				created_at        BIGINT NOT NULL,
+				updated_at        BIGINT NOT NULL,
+				secret_hash       TEXT NOT NULL
+			)`,
+		},
+	},
+}
+
+// MigrateSQL brings the schema up to the last migration, applying each one
+// missing in its own transaction, and returns how many it applied.
+func MigrateSQL(ctx context.Context, db *sql.DB, dialect SQLDialect) (int, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range sqlMigrations {
+		if migration.version <= current {
+			continue
+		}
+
+		if err := applySQLMigration(ctx, db, dialect, migration); err != nil {
+			return applied, fmt.Errorf("migration %d: %v", migration.version, err)
+		}
+
+		applied++
+	}
+
+	return applied, nil
+}
+
+func applySQLMigration(ctx context.Context, db *sql.DB, dialect SQLDialect, migration sqlMigration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range migration.statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), migration.version, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestSQLDialect_Rebind(t *testing.T) {
+	query := `SELECT id FROM lobbies WHERE status = ? AND created_at < ? LIMIT ?`
+
+	if rebound := SQLite.rebind(query); rebound != query {
+		t.Errorf("Expected the SQLite query unchanged, got %s", rebound)
+	}
+
+	expected := `SELECT id FROM lobbies WHERE status = $1 AND created_at < $2 LIMIT $3`
+	if rebound := Postgres.rebind(query); rebound != expected {
+		t.Errorf("Expected %s, got %s", expected, rebound)
+	}
+}
+
+func TestSQLDialect_IsUniqueViolation_Postgres(t *testing.T) {
+	violation := &pgconn.PgError{Code: "23505", ConstraintName: "lobbies_access_code_key"}
+
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"access code taken", violation, true},
+		{"wrapped", fmt.Errorf("saving: %w", violation), true},
+		{"another constraint", &pgconn.PgError{Code: "23505", ConstraintName: "lobbies_pkey"}, false},
+		{"another error", &pgconn.PgError{Code: "23503", ConstraintName: "lobbies_access_code_key"}, false},
+		{"not from PostgreSQL", errors.New("lobbies_access_code_key"), false},
+		{"no error", nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if violation := Postgres.isUniqueViolation(c.err, "lobbies", "access_code"); violation != c.expected {
+				t.Errorf("Expected %t, got %t", c.expected, violation)
+			}
+		})
+	}
+}
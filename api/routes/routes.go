@@ -18,14 +18,28 @@ func RegisterRoutes() *mux.Router {
 	router := mux.NewRouter()
 	router.Use(contentTypeMiddleware)
 
+	router.HandleFunc("/profiles", http.CreateProfile).Methods("POST")
+	router.HandleFunc("/profiles/{id}", http.GetProfile).Methods("GET")
+	router.HandleFunc("/profiles/{id}", http.UpdateProfile).Methods("PATCH")
 	router.HandleFunc("/lobbies", http.CreateLobby).Methods("POST")
+	router.HandleFunc("/lobbies", http.ListLobbies).Methods("GET")
 	router.HandleFunc("/lobbies/{accessCode}", http.GetLobby).Methods("GET")
+	router.HandleFunc("/lobbies/{accessCode}/actions", http.GetLobbyActions).Methods("GET")
+	router.HandleFunc("/lobbies/{accessCode}/timeline", http.GetLobbyTimeline).Methods("GET")
 	router.HandleFunc("/lobbies/{accessCode}/join", http.JoinLobby).Methods("POST")
 	router.HandleFunc("/lobbies/{accessCode}/join/mentor", http.JoinMentor).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/leave", http.LeaveLobby).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/rejoin", http.RejoinLobby).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/kick/{profileId}", http.KickProfile).Methods("POST")
 	router.HandleFunc("/lobbies/{accessCode}/select/player", http.SelectPlayer).Methods("POST")
 	router.HandleFunc("/lobbies/{accessCode}/select/team", http.SelectTeam).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/undo", http.UndoSelection).Methods("POST")
 	router.HandleFunc("/lobbies/{accessCode}/close", http.CloseLobby).Methods("POST")
 	router.HandleFunc("/lobbies/{accessCode}/promote/{playerId}", http.PromotePlayer).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/teams/balance", http.BalanceTeams).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/{action:start|pause|resume|finish|archive}", http.ChangeLobbyStatus).Methods("POST")
+	router.HandleFunc("/lobbies/{accessCode}/ws", http.LobbyWebSocket).Methods("GET")
+	router.HandleFunc("/lobbies/{accessCode}/events", http.LobbyEventStream).Methods("GET")
 
 	return router
 }
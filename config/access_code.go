@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+// LoadAccessCodeGenerator reads PAQ_ACCESS_CODE_LENGTH, the number of
+// characters of the access codes of new lobbies, 6 by default and at least 4.
+func LoadAccessCodeGenerator() (lobby.AccessCodeGenerator, error) {
+	generator := lobby.DefaultAccessCodeGenerator()
+
+	if length := os.Getenv("PAQ_ACCESS_CODE_LENGTH"); length != "" {
+		parsed, err := strconv.Atoi(length)
+		if err != nil {
+			return generator, fmt.Errorf("invalid PAQ_ACCESS_CODE_LENGTH: %v", err)
+		}
+
+		generator.Length = parsed
+	}
+
+	if err := generator.Validate(); err != nil {
+		return generator, fmt.Errorf("invalid PAQ_ACCESS_CODE_LENGTH: %v", err)
+	}
+
+	return generator, nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"crypto/subtle"
+	"log"
+	"os"
+)
+
+// AdminToken is the credential, sent as the X-Admin-Token header, that lets
+// operators list the lobbies of every master.
+type AdminToken string
+
+// LoadAdminToken reads PAQ_ADMIN_TOKEN. Without it only masters can list
+// lobbies, and only their own.
+func LoadAdminToken() AdminToken {
+	token := AdminToken(os.Getenv("PAQ_ADMIN_TOKEN"))
+
+	if token == "" {
+		log.Println("PAQ_ADMIN_TOKEN is not set, lobbies can only be listed by their masters")
+	}
+
+	return token
+}
+
+// Allows reports whether the request token is the admin one.
+func (t AdminToken) Allows(token string) bool {
+	return t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1
+}
@@ -1,32 +1,64 @@
 package config
 
 import (
+	"context"
 	"log"
 
-	"github.com/paq-devs/paq-be-rpg/api/repository"
+	"github.com/paq-devs/paq-be-rpg/internal/auth"
 	"github.com/paq-devs/paq-be-rpg/internal/lobby"
 )
 
 type Module struct {
-	LobbyService *lobby.LobbyService
+	LobbyService   *lobby.LobbyService
+	ProfileService *lobby.ProfileService
+	Sessions       *auth.Signer
+	Origins        AllowedOrigins
+	Admin          AdminToken
 }
 
 var module = Module{}
 
 func Init() {
-	mongoCfg := MongoConfig{ // TO-DO: Environment variables
-		URI:            "mongodb://localhost:27017",
-		DatabaseName:   "paq_rpg",
-		CollectionName: "lobby",
+	storageCfg, err := LoadStorageConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storage, err := OpenStorage(storageCfg)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	db, _, err := ConnectMongoDB(mongoCfg)
+	module.LobbyService = lobby.NewLobbyService(storage.Lobbies, storage.History)
+
+	accessCodes, err := LoadAccessCodeGenerator()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := module.LobbyService.SetAccessCodeGenerator(accessCodes); err != nil {
+		log.Fatal(err)
+	}
+	module.ProfileService = lobby.NewProfileService(storage.Profiles)
+
+	err = module.LobbyService.RestoreTurnTimers(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sessionCfg, err := LoadSessionConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	module.Sessions = auth.NewSigner(sessionCfg.Key, sessionCfg.TTL)
+
+	module.Origins, err = LoadAllowedOrigins()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	repo := repository.NewMongoLobbyRepository(db, mongoCfg.CollectionName)
-	module.LobbyService = lobby.NewLobbyService(repo)
+	module.Admin = LoadAdminToken()
 }
 
 func GetModule() *Module {
@@ -11,9 +11,11 @@ import (
 )
 
 type MongoConfig struct {
-	URI            string
-	DatabaseName   string
-	CollectionName string
+	URI                    string
+	DatabaseName           string
+	CollectionName         string
+	EventsCollectionName   string
+	ProfilesCollectionName string
 }
 
 func ConnectMongoDB(cfg MongoConfig) (*mongo.Database, *mongo.Collection, error) {
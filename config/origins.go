@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// AllowedOrigins are the front-end origins, such as https://paq.example.com,
+// whose pages may open the lobby WebSocket.
+type AllowedOrigins map[string]bool
+
+// LoadAllowedOrigins reads PAQ_ALLOWED_ORIGINS, a comma separated list of
+// origins. Without it no browser page can open the lobby WebSocket.
+func LoadAllowedOrigins() (AllowedOrigins, error) {
+	origins := AllowedOrigins{}
+
+	for _, value := range strings.Split(os.Getenv("PAQ_ALLOWED_ORIGINS"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+			return nil, fmt.Errorf("invalid PAQ_ALLOWED_ORIGINS: %q is not an origin such as https://paq.example.com", value)
+		}
+
+		origins[strings.ToLower(parsed.Scheme+"://"+parsed.Host)] = true
+	}
+
+	if len(origins) == 0 {
+		log.Println("PAQ_ALLOWED_ORIGINS is not set, browsers can not open the lobby WebSocket")
+	}
+
+	return origins, nil
+}
+
+// Allows reports whether a request with the Origin header may be served.
+// Clients other than browsers send no Origin and can not be used to hijack
+// the session of a participant, so they are allowed.
+func (o AllowedOrigins) Allows(origin string) bool {
+	return origin == "" || o[strings.ToLower(origin)]
+}
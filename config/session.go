@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/auth"
+)
+
+const defaultSessionTTL = 12 * time.Hour
+
+type SessionConfig struct {
+	Key []byte
+	TTL time.Duration
+}
+
+// LoadSessionConfig reads PAQ_SESSION_KEY and PAQ_SESSION_TTL (a Go duration
+// such as "12h"). Without a key a random one is generated, which is fine for
+// local development but invalidates every token on restart.
+func LoadSessionConfig() (SessionConfig, error) {
+	cfg := SessionConfig{
+		Key: []byte(os.Getenv("PAQ_SESSION_KEY")),
+		TTL: defaultSessionTTL,
+	}
+
+	if ttl := os.Getenv("PAQ_SESSION_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid PAQ_SESSION_TTL: %v", err)
+		}
+
+		cfg.TTL = parsed
+	}
+
+	if len(cfg.Key) == 0 {
+		key, err := auth.NewRandomKey()
+		if err != nil {
+			return cfg, err
+		}
+
+		log.Println("PAQ_SESSION_KEY is not set, using a random session key")
+		cfg.Key = key
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/paq-devs/paq-be-rpg/api/repository"
+	"github.com/paq-devs/paq-be-rpg/internal/lobby"
+)
+
+type StorageDriver string
+
+const (
+	MongoStorage    StorageDriver = "mongo"
+	MemoryStorage   StorageDriver = "memory" // nothing survives a restart
+	SQLiteStorage   StorageDriver = "sqlite"
+	PostgresStorage StorageDriver = "postgres"
+)
+
+type StorageConfig struct {
+	Driver      StorageDriver
+	Mongo       MongoConfig
+	SQLitePath  string
+	PostgresDSN string
+}
+
+// Storage holds the repositories the services are built with.
+type Storage struct {
+	Lobbies  lobby.LobbyRepository
+	History  lobby.LobbyEventStore
+	Profiles lobby.ProfileRepository
+}
+
+// LoadStorageConfig reads PAQ_STORAGE, mongo (the default), memory, sqlite or
+// postgres, PAQ_SQLITE_PATH, the database file of the sqlite driver, and
+// PAQ_POSTGRES_DSN, the database the postgres driver connects to. Neither
+// memory nor sqlite needs any infrastructure.
+func LoadStorageConfig() (StorageConfig, error) {
+	cfg := StorageConfig{
+		Driver: MongoStorage,
+		Mongo: MongoConfig{ // TO-DO: Environment variables
+			URI:                    "mongodb://localhost:27017",
+			DatabaseName:           "paq_rpg",
+			CollectionName:         "lobby",
+			EventsCollectionName:   "lobby_events",
+			ProfilesCollectionName: "profiles",
+		},
+		SQLitePath: "paq.db",
+	}
+
+	if driver := os.Getenv("PAQ_STORAGE"); driver != "" {
+		cfg.Driver = StorageDriver(driver)
+	}
+
+	if path := os.Getenv("PAQ_SQLITE_PATH"); path != "" {
+		cfg.SQLitePath = path
+	}
+
+	cfg.PostgresDSN = os.Getenv("PAQ_POSTGRES_DSN")
+
+	switch cfg.Driver {
+	case MongoStorage, MemoryStorage, SQLiteStorage:
+	case PostgresStorage:
+		if cfg.PostgresDSN == "" {
+			return cfg, fmt.Errorf("PAQ_POSTGRES_DSN is required when PAQ_STORAGE is postgres")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid PAQ_STORAGE %q, use mongo, memory, sqlite or postgres", cfg.Driver)
+	}
+
+	return cfg, nil
+}
+
+func OpenStorage(cfg StorageConfig) (Storage, error) {
+	if cfg.Driver == MemoryStorage {
+		log.Println("PAQ_STORAGE is memory, lobbies and profiles are lost on restart")
+
+		return Storage{
+			Lobbies:  repository.NewMemoryLobbyRepository(),
+			History:  repository.NewMemoryLobbyEventStore(),
+			Profiles: repository.NewMemoryProfileRepository(),
+		}, nil
+	}
+
+	if cfg.Driver == SQLiteStorage {
+		db, err := repository.OpenSQLite(cfg.SQLitePath)
+		if err != nil {
+			return Storage{}, err
+		}
+
+		return openSQLStorage(db, repository.SQLite, cfg.SQLitePath)
+	}
+
+	if cfg.Driver == PostgresStorage {
+		db, err := repository.OpenPostgres(cfg.PostgresDSN)
+		if err != nil {
+			return Storage{}, fmt.Errorf("connecting to PostgreSQL: %v", err)
+		}
+
+		return openSQLStorage(db, repository.Postgres, "PostgreSQL")
+	}
+
+	db, _, err := ConnectMongoDB(cfg.Mongo)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	lobbies := repository.NewMongoLobbyRepository(db, cfg.Mongo.CollectionName)
+
+	migrated, err := lobbies.Migrate(context.Background())
+	if err != nil {
+		return Storage{}, err
+	}
+
+	if migrated > 0 {
+		log.Printf("%d lobbies migrated to schema version %d", migrated, repository.LobbySchemaVersion)
+	}
+
+	if err := lobbies.EnsureIndexes(context.Background()); err != nil {
+		return Storage{}, fmt.Errorf("creating the lobby indexes: %v", err)
+	}
+
+	history := repository.NewMongoLobbyEventStore(db, cfg.Mongo.EventsCollectionName)
+	if err := history.EnsureIndexes(context.Background()); err != nil {
+		return Storage{}, fmt.Errorf("creating the lobby event indexes: %v", err)
+	}
+
+	return Storage{
+		Lobbies:  lobbies,
+		History:  history,
+		Profiles: repository.NewMongoProfileRepository(db, cfg.Mongo.ProfilesCollectionName),
+	}, nil
+}
+
+// openSQLStorage migrates the database before building the repositories,
+// which keep the lobbies, their history and the profiles in it. name is how
+// the database is logged.
+func openSQLStorage(db *sql.DB, dialect repository.SQLDialect, name string) (Storage, error) {
+	migrated, err := repository.MigrateSQL(context.Background(), db, dialect)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	if migrated > 0 {
+		log.Printf("%d SQL migrations applied to %s", migrated, name)
+	}
+
+	return Storage{
+		Lobbies:  repository.NewSQLLobbyRepository(db, dialect),
+		History:  repository.NewSQLLobbyEventStore(db, dialect),
+		Profiles: repository.NewSQLProfileRepository(db, dialect),
+	}, nil
+}
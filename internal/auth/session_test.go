@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	signer := NewSigner([]byte("test-key"), time.Hour)
+	master := profile.NewMaster("Master", "avatar")
+
+	token, err := signer.Issue(master, "abc123")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	claims, err := signer.Verify(token, "abc123")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	if claims.ProfileID != master.ID {
+		t.Errorf("Expected ProfileID to be %s, got %s", master.ID, claims.ProfileID)
+	}
+
+	if claims.Role != profile.Master {
+		t.Errorf("Expected Role to be Master, got %s", claims.Role)
+	}
+}
+
+func TestVerify_WhenTokenIsTampered(t *testing.T) {
+	signer := NewSigner([]byte("test-key"), time.Hour)
+	other := NewSigner([]byte("other-key"), time.Hour)
+
+	token, _ := other.Issue(profile.NewMaster("Master", "avatar"), "abc123")
+
+	if _, err := signer.Verify(token, "abc123"); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+
+	if _, err := signer.Verify("not-a-token", "abc123"); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+
+	if _, err := signer.Verify("", "abc123"); err != ErrMissingToken {
+		t.Errorf("Expected ErrMissingToken, got %v", err)
+	}
+}
+
+func TestVerify_WhenTokenIsExpired(t *testing.T) {
+	signer := NewSigner([]byte("test-key"), time.Minute)
+	token, _ := signer.Issue(profile.NewMaster("Master", "avatar"), "abc123")
+
+	signer.now = func() time.Time {
+		return time.Now().Add(2 * time.Minute)
+	}
+
+	if _, err := signer.Verify(token, "abc123"); err != ErrTokenExpired {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestVerify_WhenTokenIsForAnotherLobby(t *testing.T) {
+	signer := NewSigner([]byte("test-key"), time.Hour)
+	token, _ := signer.Issue(profile.NewMaster("Master", "avatar"), "abc123")
+
+	if _, err := signer.Verify(token, "def456"); err != ErrWrongLobby {
+		t.Errorf("Expected ErrWrongLobby, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package lobby
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// AccessCodeAlphabet leaves out the characters easily mistaken for one
+// another when a code is read aloud or typed: 0 and O, 1, I and L.
+const AccessCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+const (
+	DefaultAccessCodeLength = 6
+	MinAccessCodeLength     = 4
+
+	// maxAccessCodeAttempts bounds how many codes CreateLobby tries when the
+	// repository reports the code is taken.
+	maxAccessCodeAttempts = 5
+)
+
+// AccessCodeGenerator draws random access codes of Length characters from
+// AccessCodeAlphabet. With the default length there are about 887 million
+// codes.
+type AccessCodeGenerator struct {
+	Length int
+}
+
+func DefaultAccessCodeGenerator() AccessCodeGenerator {
+	return AccessCodeGenerator{Length: DefaultAccessCodeLength}
+}
+
+func (g AccessCodeGenerator) Validate() error {
+	if g.Length < MinAccessCodeLength {
+		return ErrInvalidAccessCodeLength.WithDetails(map[string]interface{}{"length": g.Length, "min": MinAccessCodeLength})
+	}
+
+	return nil
+}
+
+func (g AccessCodeGenerator) Generate() string {
+	max := big.NewInt(int64(len(AccessCodeAlphabet)))
+
+	code := make([]byte, g.Length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err) // crypto/rand only fails when the system has no source of randomness
+		}
+
+		code[i] = AccessCodeAlphabet[n.Int64()]
+	}
+
+	return string(code)
+}
+
+// WithAccessCode sets the code players join the lobby with, instead of a
+// random one of the default length.
+func WithAccessCode(code string) LobbyOption {
+	return func(l *Lobby) {
+		l.AccessCode = code
+	}
+}
@@ -0,0 +1,123 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// TakenAccessCodeRepositoryMock reports the access code as taken for the
+// first Taken saves, as if other lobbies had drawn the same codes.
+type TakenAccessCodeRepositoryMock struct {
+	*LobbyRepositoryMock
+	Taken int
+	Codes []string
+}
+
+func (r *TakenAccessCodeRepositoryMock) Save(ctx context.Context, lobby *Lobby) error {
+	r.Codes = append(r.Codes, lobby.AccessCode)
+
+	if r.Taken > 0 {
+		r.Taken--
+		return ErrAccessCodeTaken
+	}
+
+	return r.LobbyRepositoryMock.Save(ctx, lobby)
+}
+
+func TestAccessCodeGenerator_Generate(t *testing.T) {
+	generator := AccessCodeGenerator{Length: 8}
+
+	for i := 0; i < 100; i++ {
+		code := generator.Generate()
+
+		if len(code) != 8 {
+			t.Fatalf("Expected a code of 8 characters, got %q", code)
+		}
+
+		for _, c := range code {
+			if !strings.ContainsRune(AccessCodeAlphabet, c) {
+				t.Fatalf("Expected only characters of %s, got %q", AccessCodeAlphabet, code)
+			}
+		}
+	}
+}
+
+func TestAccessCodeAlphabet_IsUnambiguous(t *testing.T) {
+	for _, c := range "01OIL" {
+		if strings.ContainsRune(AccessCodeAlphabet, c) {
+			t.Errorf("Expected %q not to be in the alphabet", c)
+		}
+	}
+}
+
+func TestAccessCodeGenerator_Validate(t *testing.T) {
+	if err := DefaultAccessCodeGenerator().Validate(); err != nil {
+		t.Errorf("Expected the default generator to be valid, got %v", err)
+	}
+
+	if err := (AccessCodeGenerator{Length: 3}).Validate(); !errors.Is(err, ErrInvalidAccessCodeLength) {
+		t.Errorf("Expected ErrInvalidAccessCodeLength, got %v", err)
+	}
+}
+
+func TestNewLobby_AccessCode(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 1)
+
+	if len(lobby.AccessCode) != DefaultAccessCodeLength {
+		t.Errorf("Expected an access code of %d characters, got %q", DefaultAccessCodeLength, lobby.AccessCode)
+	}
+
+	if lobby.CreatedAt == 0 || lobby.CreatedAt != lobby.StatusTimestamps[Waiting] {
+		t.Errorf("Expected CreatedAt to be when the lobby entered Waiting, got %d", lobby.CreatedAt)
+	}
+
+	lobby = NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 1, WithAccessCode("ABCD"))
+	if lobby.AccessCode != "ABCD" {
+		t.Errorf("Expected AccessCode to be ABCD, got %s", lobby.AccessCode)
+	}
+}
+
+func TestCreateLobby_RetriesTakenAccessCode(t *testing.T) {
+	repo := &TakenAccessCodeRepositoryMock{LobbyRepositoryMock: NewLobbyRepositoryMock(), Taken: 2}
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	if err := service.SetAccessCodeGenerator(AccessCodeGenerator{Length: 10}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := service.CreateLobby(context.Background(), profile.NewMaster("Master", "avatar"), "Test", 1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(repo.Codes) != 3 {
+		t.Errorf("Expected 3 saves, got %d", len(repo.Codes))
+	}
+
+	if response.AccessCode != repo.Codes[2] || len(response.AccessCode) != 10 {
+		t.Errorf("Expected the access code of the last save, %s, got %s", repo.Codes[2], response.AccessCode)
+	}
+
+	events, err := service.history.Load(context.Background(), response.AccessCode)
+	if err != nil || len(events) == 0 || events[0].AccessCode != response.AccessCode {
+		t.Errorf("Expected the history to be recorded under %s, got %+v and %v", response.AccessCode, events, err)
+	}
+}
+
+func TestCreateLobby_GivesUpOnTakenAccessCode(t *testing.T) {
+	repo := &TakenAccessCodeRepositoryMock{LobbyRepositoryMock: NewLobbyRepositoryMock(), Taken: maxAccessCodeAttempts}
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	_, err := service.CreateLobby(context.Background(), profile.NewMaster("Master", "avatar"), "Test", 1, 1)
+	if !errors.Is(err, ErrAccessCodeTaken) {
+		t.Errorf("Expected ErrAccessCodeTaken, got %v", err)
+	}
+
+	if len(repo.Codes) != maxAccessCodeAttempts {
+		t.Errorf("Expected %d saves, got %d", maxAccessCodeAttempts, len(repo.Codes))
+	}
+}
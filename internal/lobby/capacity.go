@@ -0,0 +1,116 @@
+package lobby
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+// Capacity limits how many profiles a lobby takes and how large its teams
+// can be. Zero means no limit. Players per team count the leader.
+type Capacity struct {
+	MaxPlayers        int
+	MaxMentors        int
+	MinPlayersPerTeam int
+	MaxPlayersPerTeam int
+}
+
+func (c Capacity) Validate() error {
+	details := map[string]interface{}{
+		"max_players":          c.MaxPlayers,
+		"max_mentors":          c.MaxMentors,
+		"min_players_per_team": c.MinPlayersPerTeam,
+		"max_players_per_team": c.MaxPlayersPerTeam,
+	}
+
+	if c.MaxPlayers < 0 || c.MaxMentors < 0 || c.MinPlayersPerTeam < 0 || c.MaxPlayersPerTeam < 0 {
+		return ErrInvalidCapacity.WithDetails(details)
+	}
+
+	if c.MaxPlayersPerTeam > 0 && c.MinPlayersPerTeam > c.MaxPlayersPerTeam {
+		return ErrInvalidCapacity.WithDetails(details)
+	}
+
+	return nil
+}
+
+// WithCapacity limits the number of players and mentors of the lobby and the
+// size of its teams.
+func WithCapacity(capacity Capacity) LobbyOption {
+	return func(l *Lobby) {
+		l.Capacity = capacity
+	}
+}
+
+func (l *Lobby) hasRoomFor(p profile.Profile) bool {
+	if p.Role == profile.Mentor {
+		return l.MaxMentors == 0 || len(l.Mentors) < l.MaxMentors
+	}
+
+	return l.MaxPlayers == 0 || len(l.Players) < l.MaxPlayers
+}
+
+func (l *Lobby) addToWaitlist(p profile.Profile) {
+	l.Waitlist = append(l.Waitlist, p)
+	l.emit(ProfileWaitlisted, ResponseFromProfile(&p))
+}
+
+// promoteFromWaitlist moves into the lobby, in order of arrival, the
+// waitlisted profiles there is room for again.
+func (l *Lobby) promoteFromWaitlist() {
+	waiting := make([]profile.Profile, 0, len(l.Waitlist))
+
+	for _, p := range l.Waitlist {
+		if !l.hasRoomFor(p) {
+			waiting = append(waiting, p)
+			continue
+		}
+
+		if p.Role == profile.Mentor {
+			l.Mentors = append(l.Mentors, p)
+		} else {
+			l.Players = append(l.Players, p)
+		}
+
+		l.emit(WaitlistPromoted, ResponseFromProfile(&p))
+	}
+
+	l.Waitlist = waiting
+}
+
+// dismissWaitlist sends away the profiles still waitlisted when the lobby
+// closes, since no spot will be freed for them anymore.
+func (l *Lobby) dismissWaitlist() {
+	for _, p := range l.Waitlist {
+		l.emit(WaitlistDismissed, ResponseFromProfile(&p))
+	}
+
+	l.Waitlist = nil
+}
+
+func (l *Lobby) removeFromWaitlist(profileID string) *profile.Profile {
+	for i, p := range l.Waitlist {
+		if p.ID == profileID {
+			l.Waitlist = append(l.Waitlist[:i], l.Waitlist[i+1:]...)
+			return &p
+		}
+	}
+
+	return nil
+}
+
+// checkTeamSizes returns ErrTeamSizeUnsatisfiable when the players can not
+// be split into one team per mentor within the team size limits.
+func (l *Lobby) checkTeamSizes() error {
+	teams, players := len(l.Mentors), len(l.Players)
+
+	tooFew := l.MinPlayersPerTeam > 0 && players < teams*l.MinPlayersPerTeam
+	tooMany := l.MaxPlayersPerTeam > 0 && players > teams*l.MaxPlayersPerTeam
+
+	if tooFew || tooMany {
+		return ErrTeamSizeUnsatisfiable.WithDetails(map[string]interface{}{
+			"players":              players,
+			"teams":                teams,
+			"min_players_per_team": l.MinPlayersPerTeam,
+			"max_players_per_team": l.MaxPlayersPerTeam,
+		})
+	}
+
+	return nil
+}
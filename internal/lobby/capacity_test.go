@@ -0,0 +1,171 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func newPlayer(name string) profile.Profile {
+	return profile.NewPlayer(name, "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+}
+
+func TestCapacity_Validate(t *testing.T) {
+	cases := []struct {
+		capacity Capacity
+		valid    bool
+	}{
+		{Capacity{}, true},
+		{Capacity{MaxPlayers: 10, MaxMentors: 2, MinPlayersPerTeam: 3, MaxPlayersPerTeam: 5}, true},
+		{Capacity{MinPlayersPerTeam: 3}, true},
+		{Capacity{MinPlayersPerTeam: 3, MaxPlayersPerTeam: 3}, true},
+		{Capacity{MaxPlayers: -1}, false},
+		{Capacity{MinPlayersPerTeam: 4, MaxPlayersPerTeam: 3}, false},
+	}
+
+	for _, c := range cases {
+		err := c.capacity.Validate()
+
+		if c.valid && err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", c.capacity, err)
+		}
+
+		if !c.valid && !errors.Is(err, ErrInvalidCapacity) {
+			t.Errorf("Expected ErrInvalidCapacity for %+v, got %v", c.capacity, err)
+		}
+	}
+}
+
+func TestJoin_WhenFull(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2, WithCapacity(Capacity{MaxPlayers: 2, MaxMentors: 1}))
+
+	first, second, third, fourth := newPlayer("First"), newPlayer("Second"), newPlayer("Third"), newPlayer("Fourth")
+	mentor, secondMentor := profile.NewMentor("Mentor", "avatar"), profile.NewMentor("Second Mentor", "avatar")
+
+	for _, p := range []profile.Profile{first, second, mentor, third, secondMentor, fourth} {
+		if err := lobby.Join(p); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if len(lobby.Players) != 2 || len(lobby.Mentors) != 1 {
+		t.Errorf("Expected 2 players and 1 mentor, got %d and %d", len(lobby.Players), len(lobby.Mentors))
+	}
+
+	if len(lobby.Waitlist) != 3 || lobby.Waitlist[0].ID != third.ID || lobby.Waitlist[1].ID != secondMentor.ID || lobby.Waitlist[2].ID != fourth.ID {
+		t.Errorf("Expected the waitlist in order of arrival, got %+v", lobby.Waitlist)
+	}
+
+	_ = lobby.Leave(first.ID)
+
+	if lobby.getPlayer(third.ID) == nil {
+		t.Errorf("Expected the first waitlisted player to be promoted")
+	}
+
+	if len(lobby.Waitlist) != 2 || lobby.Waitlist[0].ID != secondMentor.ID {
+		t.Errorf("Expected the mentor to stay first in the waitlist, got %+v", lobby.Waitlist)
+	}
+
+	_ = lobby.Kick(lobby.Master.ID, mentor.ID)
+
+	if lobby.getMentor(secondMentor.ID) == nil {
+		t.Errorf("Expected the waitlisted mentor to be promoted")
+	}
+
+	_ = lobby.Leave(fourth.ID)
+
+	if len(lobby.Waitlist) != 0 {
+		t.Errorf("Expected the player to leave the waitlist, got %+v", lobby.Waitlist)
+	}
+
+	if len(lobby.Departed) != 1 {
+		t.Errorf("Expected only the player who left the lobby to be able to rejoin, got %+v", lobby.Departed)
+	}
+
+	_ = lobby.Rejoin(first.ID)
+
+	if len(lobby.Waitlist) != 1 || lobby.Waitlist[0].ID != first.ID {
+		t.Errorf("Expected the rejoining player to be waitlisted, got %+v", lobby.Waitlist)
+	}
+}
+
+func TestStartTeamCreation_DismissesWaitlist(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2, WithCapacity(Capacity{MaxPlayers: 2, MaxMentors: 1}))
+
+	waitlisted, secondMentor := newPlayer("Waitlisted"), profile.NewMentor("Second Mentor", "avatar")
+	for _, p := range []profile.Profile{newPlayer("First"), newPlayer("Second"), profile.NewMentor("Mentor", "avatar"), waitlisted, secondMentor} {
+		if err := lobby.Join(p); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if err := lobby.StartTeamCreation(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Waitlist) != 0 {
+		t.Errorf("Expected the waitlist to be empty, got %+v", lobby.Waitlist)
+	}
+
+	events := lobby.PullEvents()
+
+	dismissed := make([]string, 0)
+	for _, event := range events {
+		if event.Type == WaitlistDismissed {
+			dismissed = append(dismissed, event.Payload.(ProfileResponse).ID)
+		}
+	}
+
+	if len(dismissed) != 2 || dismissed[0] != waitlisted.ID || dismissed[1] != secondMentor.ID {
+		t.Errorf("Expected %s and %s to be dismissed in order of arrival, got %v", waitlisted.ID, secondMentor.ID, dismissed)
+	}
+
+	replayed, err := Replay(events)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !replayed.SameState(lobby) {
+		t.Errorf("Expected the replayed lobby to have no waitlist, got %+v", replayed.Waitlist)
+	}
+}
+
+func TestStartTeamCreation_WhenTeamSizesCanNotBeMet(t *testing.T) {
+	cases := []struct {
+		name     string
+		capacity Capacity
+		players  int
+		mentors  int
+		valid    bool
+	}{
+		{"no limits", Capacity{}, 3, 2, true},
+		{"too few players", Capacity{MinPlayersPerTeam: 2}, 3, 2, false},
+		{"exactly the minimum", Capacity{MinPlayersPerTeam: 2}, 4, 2, true},
+		{"too many players", Capacity{MaxPlayersPerTeam: 2}, 5, 2, false},
+		{"exactly the maximum", Capacity{MaxPlayersPerTeam: 2}, 4, 2, true},
+		{"between the limits", Capacity{MinPlayersPerTeam: 2, MaxPlayersPerTeam: 3}, 5, 2, true},
+	}
+
+	for _, c := range cases {
+		lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2, WithCapacity(c.capacity))
+
+		for i := 0; i < c.players; i++ {
+			_ = lobby.Join(newPlayer("Player"))
+		}
+
+		for i := 0; i < c.mentors; i++ {
+			_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+		}
+
+		err := lobby.StartTeamCreation()
+
+		if c.valid && err != nil {
+			t.Errorf("Expected no error with %s, got %v", c.name, err)
+		}
+
+		if !c.valid && !errors.Is(err, ErrTeamSizeUnsatisfiable) {
+			t.Errorf("Expected ErrTeamSizeUnsatisfiable with %s, got %v", c.name, err)
+		}
+	}
+}
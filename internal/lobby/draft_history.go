@@ -0,0 +1,149 @@
+package lobby
+
+import (
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// DraftAction is a selection made during the draft. The actions are kept in
+// order on the lobby, so the Master can undo them.
+type DraftAction struct {
+	Type          ChooseType  // SelectTeam or SelectPlayer
+	Phase         LobbyStatus // status of the lobby when the selection was made
+	TeamID        int
+	Leader        profile.Profile // the leader who chose, as it was before choosing
+	Player        profile.Profile // the chosen player, empty for SelectTeam
+	Deadline      int64           // deadline of the turn the selection was made in, 0 without one
+	Timestamp     int64
+	TurnStartedAt int64 // when the turn the selection was made in started, 0 when stored before it was recorded
+}
+
+type SelectionUndonePayload struct {
+	Type     ChooseType       `json:"type"`
+	TeamID   int              `json:"team_id"`
+	LeaderID string           `json:"leader_id"`
+	Player   *ProfileResponse `json:"player,omitempty"`
+}
+
+func (l *Lobby) recordDraftAction(action DraftAction) {
+	action.Phase = l.Status
+	action.TurnStartedAt = l.turnStartedAt()
+	if l.ChooseControl != nil {
+		action.Deadline = l.ChooseControl.Deadline
+	}
+
+	l.DraftHistory = append(l.DraftHistory, action)
+}
+
+// turnStartedAt returns when the current turn started: a turn with a
+// deadline started one turn timeout before it, any other with the previous
+// selection of the phase or, for the first one, with the phase.
+func (l *Lobby) turnStartedAt() int64 {
+	if l.ChooseControl != nil && l.ChooseControl.Deadline > 0 {
+		return time.Unix(l.ChooseControl.Deadline, 0).Add(-l.TurnTimeout).Unix()
+	}
+
+	if last := len(l.DraftHistory) - 1; last >= 0 && l.DraftHistory[last].Phase == l.Status {
+		return l.DraftHistory[last].Timestamp
+	}
+
+	return l.StatusTimestamps[l.Status]
+}
+
+// UndoSelection reverts the most recent selection: the team loses its
+// leader or its last player, who goes back to Players, and the turn goes
+// back to the leader who chose. Selections can be undone one by one back to
+// the start of the current phase. Right after the last pick, the draft is
+// reopened in PlayerSelect.
+func (l *Lobby) UndoSelection() error {
+	if _, err := l.transition(UndoAction); err != nil {
+		return err
+	}
+
+	phase, err := l.undoPhase()
+	if err != nil {
+		return err
+	}
+
+	action, err := l.revertDraftAction()
+	if err != nil {
+		return err
+	}
+
+	payload := SelectionUndonePayload{Type: action.Type, TeamID: action.TeamID, LeaderID: action.Leader.ID}
+	if action.Type == SelectPlayer {
+		player := ResponseFromProfile(&action.Player)
+		payload.Player = &player
+	}
+
+	l.emit(SelectionUndone, payload)
+	l.setStatus(phase)
+	l.setChooseControl(&ChooseControl{Type: action.Type, ChoosingNow: action.Leader})
+
+	return nil
+}
+
+// revertDraftAction removes the most recent draft action from the history
+// and from the team it changed, returning the selected profile to Players.
+func (l *Lobby) revertDraftAction() (DraftAction, error) {
+	last := len(l.DraftHistory) - 1
+	if last < 0 {
+		return DraftAction{}, ErrNothingToUndo
+	}
+
+	action := l.DraftHistory[last]
+	team := l.getTeam(action.TeamID)
+	if team == nil {
+		return DraftAction{}, ErrTeamNotFound
+	}
+
+	switch action.Type {
+	case SelectTeam:
+		team.Leader = profile.Profile{}
+		l.Players = append(l.Players, action.Leader)
+	case SelectPlayer:
+		for i, p := range team.Players {
+			if p.ID == action.Player.ID {
+				team.Players = append(team.Players[:i], team.Players[i+1:]...)
+				break
+			}
+		}
+
+		l.Players = append(l.Players, action.Player)
+	}
+
+	l.DraftHistory = l.DraftHistory[:last]
+	return action, nil
+}
+
+func (l *Lobby) getTeam(id int) *Team {
+	for _, t := range l.Teams {
+		if t.ID == id {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// undoPhase returns the phase the most recent selection belongs to, or
+// ErrNothingToUndo when it was made before the current phase started.
+func (l *Lobby) undoPhase() (LobbyStatus, error) {
+	phase := l.Status
+	if phase == ReadyToStart {
+		phase = PlayerSelect
+	}
+
+	last := len(l.DraftHistory) - 1
+	if last < 0 || l.DraftHistory[last].Phase != phase {
+		return "", ErrNothingToUndo
+	}
+
+	return phase, nil
+}
+
+func draftToUndo(l *Lobby, actor profile.Profile) error {
+	_, err := l.undoPhase()
+	return err
+}
@@ -0,0 +1,205 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestUndoSelection_PlayerSelect(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 3)
+
+	err := lobby.UndoSelection()
+
+	if !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo before any pick, got %v", err)
+	}
+
+	first := lobby.ChooseControl.ChoosingNow
+	firstPick := lobby.Players[0]
+	_ = lobby.SelectPlayer(first, firstPick.ID)
+
+	second := lobby.ChooseControl.ChoosingNow
+	secondPick := lobby.Players[0]
+	_ = lobby.SelectPlayer(second, secondPick.ID)
+
+	err = lobby.UndoSelection()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if lobby.ChooseControl.ChoosingNow.ID != second.ID {
+		t.Errorf("Expected the turn to go back to %s, got %s", second.Name, lobby.ChooseControl.ChoosingNow.Name)
+	}
+
+	if len(lobby.getTeamByLeaderID(second.ID).Players) != 0 {
+		t.Errorf("Expected the player to leave the team, got %+v", lobby.getTeamByLeaderID(second.ID).Players)
+	}
+
+	if lobby.getPlayer(secondPick.ID) == nil {
+		t.Errorf("Expected the player to be back in Players")
+	}
+
+	_ = lobby.UndoSelection()
+
+	if lobby.ChooseControl.ChoosingNow.ID != first.ID || len(lobby.Players) != 3 {
+		t.Errorf("Expected the draft back at the start, got %s choosing with %d players", lobby.ChooseControl.ChoosingNow.Name, len(lobby.Players))
+	}
+
+	err = lobby.UndoSelection()
+
+	if !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo at the start of the phase, got %v", err)
+	}
+
+	if len(lobby.DraftHistory) != 0 {
+		t.Errorf("Expected the history to be empty, got %+v", lobby.DraftHistory)
+	}
+}
+
+func TestUndoSelection_AfterLastPick(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 2)
+
+	for lobby.Status == PlayerSelect {
+		_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	}
+
+	if err := lobby.Can(UndoAction, lobby.Master); err != nil {
+		t.Errorf("Expected the master to be able to undo, got %v", err)
+	}
+
+	err := lobby.UndoSelection()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if lobby.Status != PlayerSelect {
+		t.Errorf("Expected Status to be PlayerSelect, got %v", lobby.Status)
+	}
+
+	if lobby.ChooseControl == nil || lobby.ChooseControl.Type != SelectPlayer || len(lobby.Players) != 1 {
+		t.Errorf("Expected the last pick to be open again, got %+v", lobby.ChooseControl)
+	}
+}
+
+func TestUndoSelection_LeaderTeamSelect(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+
+	firstLeader := profile.NewPlayer("First Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership})
+	secondLeader := profile.NewPlayer("Second Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Communication})
+
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(firstLeader)
+	_ = lobby.Join(secondLeader)
+	_ = lobby.Join(newPlayer("Player"))
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	_ = lobby.StartLeaderTeamSelection()
+
+	choosing := lobby.ChooseControl.ChoosingNow
+	_ = lobby.SelectTeam(choosing, 0)
+
+	err := lobby.UndoSelection()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if lobby.Teams[0].Leader.ID != "" {
+		t.Errorf("Expected team 0 to be free, got %+v", lobby.Teams[0].Leader)
+	}
+
+	if lobby.getPlayer(choosing.ID) == nil {
+		t.Errorf("Expected the leader to be back in Players")
+	}
+
+	if lobby.ChooseControl.ChoosingNow.ID != choosing.ID || lobby.ChooseControl.Type != SelectTeam {
+		t.Errorf("Expected %s to choose a team again, got %+v", choosing.Name, lobby.ChooseControl)
+	}
+
+	err = lobby.SelectTeam(choosing, 0)
+
+	if err != nil || lobby.Teams[0].Leader.ID != choosing.ID {
+		t.Errorf("Expected the leader to choose again, got %v", err)
+	}
+}
+
+func TestUndoSelection_WhenTeamsWereBalanced(t *testing.T) {
+	lobby := newBalancedLobby()
+	_ = lobby.BalanceTeams(1)
+
+	err := lobby.Can(UndoAction, lobby.Master)
+
+	if !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, got %v", err)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 3)
+	lobby.TurnTimeout = time.Minute
+	lobby.StatusTimestamps[PlayerSelect] = time.Now().Unix() - 30
+
+	for lobby.Status == PlayerSelect {
+		_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	}
+
+	timeline := lobby.Timeline()
+	if len(timeline) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(timeline))
+	}
+
+	first := timeline[0]
+	if first.Position != 1 || first.Actor.Name != "A" || first.Target.Kind != "player" || first.Target.ID != lobby.DraftHistory[0].Player.ID {
+		t.Errorf("Expected A to pick the first player, got %+v", first)
+	}
+
+	if first.ElapsedSeconds < 30 {
+		t.Errorf("Expected the first turn to take at least 30 seconds, got %d", first.ElapsedSeconds)
+	}
+
+	if first.Before.ChoosingNow.Name != "A" || first.After.ChoosingNow.Name != "B" || timeline[1].Before != first.After {
+		t.Errorf("Expected the turn to go from A to B, got %+v -> %+v", first.Before, first.After)
+	}
+
+	if timeline[1].Before.Deadline == 0 {
+		t.Errorf("Expected the turns after the timeout was set to have a deadline")
+	}
+
+	if timeline[2].After != nil {
+		t.Errorf("Expected no turn after the last pick, got %+v", timeline[2].After)
+	}
+}
+
+func TestTimeline_AfterUndoFromReadyToStart(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 3)
+	lobby.StatusTimestamps[PlayerSelect] = time.Now().Unix() - 30
+
+	for lobby.Status == PlayerSelect {
+		_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	}
+
+	if lobby.Status != ReadyToStart {
+		t.Fatalf("Expected ReadyToStart, got %v", lobby.Status)
+	}
+
+	// reopening PlayerSelect moves its timestamp, not the start of the turns
+	if err := lobby.UndoSelection(); err != nil {
+		t.Fatalf("Expected the last pick to be undone, got %v", err)
+	}
+
+	timeline := lobby.Timeline()
+	if len(timeline) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(timeline))
+	}
+
+	if timeline[0].ElapsedSeconds < 30 {
+		t.Errorf("Expected the first turn to take at least 30 seconds, got %d", timeline[0].ElapsedSeconds)
+	}
+}
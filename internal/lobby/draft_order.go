@@ -0,0 +1,68 @@
+package lobby
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+// DraftOrder decides which leader picks next during PlayerSelect. Leaders are
+// ranked by SelectionPriority and every round each team picks once.
+type DraftOrder string
+
+const (
+	RoundRobin      DraftOrder = "round_robin"      // every round in priority order
+	Snake           DraftOrder = "snake"            // order reverses every round
+	ReversePriority DraftOrder = "reverse_priority" // every round in reverse priority order
+)
+
+func ParseDraftOrder(value string) (DraftOrder, error) {
+	switch order := DraftOrder(value); order {
+	case "":
+		return RoundRobin, nil
+	case RoundRobin, Snake, ReversePriority:
+		return order, nil
+	}
+
+	return "", ErrInvalidDraftOrder.WithDetails(map[string]interface{}{"draft_order": value})
+}
+
+// WithDraftOrder sets the order leaders follow when picking players.
+func WithDraftOrder(order DraftOrder) LobbyOption {
+	return func(l *Lobby) {
+		l.DraftOrder = order
+	}
+}
+
+// pickIndex returns the position, in priority order, of the team picking at
+// the given position of the round.
+func (o DraftOrder) pickIndex(round int, position int, teams int) int {
+	switch o {
+	case Snake:
+		if round%2 == 1 {
+			return teams - 1 - position
+		}
+	case ReversePriority:
+		return teams - 1 - position
+	}
+
+	return position
+}
+
+// leadersByPriority returns the team leaders sorted by SelectionPriority.
+func (l *Lobby) leadersByPriority() []profile.Profile {
+	leaders := make([]profile.Profile, 0, len(l.Teams))
+	for _, team := range l.Teams {
+		if team.Leader.ID != "" {
+			leaders = append(leaders, team.Leader)
+		}
+	}
+
+	sortByPriority(leaders)
+	return leaders
+}
+
+func (l *Lobby) pickCount() int {
+	picks := 0
+	for _, team := range l.Teams {
+		picks += len(team.Players)
+	}
+
+	return picks
+}
@@ -0,0 +1,152 @@
+package lobby
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestParseDraftOrder(t *testing.T) {
+	cases := map[string]DraftOrder{
+		"":                 RoundRobin,
+		"round_robin":      RoundRobin,
+		"snake":            Snake,
+		"reverse_priority": ReversePriority,
+	}
+
+	for value, expected := range cases {
+		order, err := ParseDraftOrder(value)
+
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", value, err)
+		}
+
+		if order != expected {
+			t.Errorf("Expected %q to parse to %v, got %v", value, expected, order)
+		}
+	}
+
+	_, err := ParseDraftOrder("random")
+
+	if !errors.Is(err, ErrInvalidDraftOrder) {
+		t.Errorf("Expected ErrInvalidDraftOrder, got %v", err)
+	}
+}
+
+// newDraftLobby returns a lobby in PlayerSelect whose leaders are named after
+// their priority rank ("A" picks first). Teams are stored in reverse priority
+// order so the draft can not rely on the position of the team.
+func newDraftLobby(order DraftOrder, teams int, players int) *Lobby {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Draft Lobby", 1, 2, WithDraftOrder(order))
+
+	for i := 0; i < teams; i++ {
+		leader := profile.NewPlayer(string(rune('A'+i)), "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership})
+		leader.Role = profile.Leader
+		leader.SelectionPriority = i + 1
+
+		team := NewTeam(teams-1-i, profile.NewMentor("Mentor", "avatar"))
+		team.Leader = leader
+		lobby.Teams = append([]*Team{&team}, lobby.Teams...)
+	}
+
+	for i := 0; i < players; i++ {
+		lobby.Players = append(lobby.Players, profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication}))
+	}
+
+	lobby.Status = PlayerSelect
+	first, _ := lobby.GetNextTeamLeaderToPick()
+	lobby.ChooseControl, _ = NewSelectPlayerChooseControl(*first)
+
+	return lobby
+}
+
+func TestSelectPlayer_DraftOrders(t *testing.T) {
+	cases := []struct {
+		name     string
+		order    DraftOrder
+		teams    int
+		players  int
+		expected string
+	}{
+		{"round robin, even", RoundRobin, 3, 6, "ABCABC"},
+		{"round robin, uneven", RoundRobin, 3, 7, "ABCABCA"},
+		{"round robin, fewer players than teams", RoundRobin, 3, 2, "AB"},
+		{"round robin, two teams", RoundRobin, 2, 5, "ABABA"},
+		{"snake, even", Snake, 3, 6, "ABCCBA"},
+		{"snake, uneven", Snake, 3, 7, "ABCCBAA"},
+		{"snake, uneven third round", Snake, 3, 8, "ABCCBAAB"},
+		{"snake, fewer players than teams", Snake, 3, 2, "AB"},
+		{"snake, two teams", Snake, 2, 5, "ABBAA"},
+		{"snake, single team", Snake, 1, 3, "AAA"},
+		{"reverse priority, even", ReversePriority, 3, 6, "CBACBA"},
+		{"reverse priority, uneven", ReversePriority, 3, 7, "CBACBAC"},
+		{"reverse priority, three rounds", ReversePriority, 3, 9, "CBACBACBA"},
+		{"reverse priority, four rounds", ReversePriority, 3, 11, "CBACBACBACB"},
+		{"reverse priority, fewer players than teams", ReversePriority, 3, 2, "CB"},
+		{"reverse priority, two teams", ReversePriority, 2, 5, "BABAB"},
+		{"reverse priority, four teams", ReversePriority, 4, 10, "DCBADCBADC"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lobby := newDraftLobby(c.order, c.teams, c.players)
+
+			picked := ""
+			for lobby.Status == PlayerSelect {
+				leader := lobby.ChooseControl.ChoosingNow
+				picked += leader.Name
+
+				err := lobby.SelectPlayer(leader, lobby.Players[0].ID)
+
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			if picked != c.expected {
+				t.Errorf("Expected pick order %s, got %s", c.expected, picked)
+			}
+
+			if lobby.Status != ReadyToStart {
+				t.Errorf("Expected Status to be ReadyToStart, got %v", lobby.Status)
+			}
+
+			if lobby.ChooseControl != nil {
+				t.Errorf("Expected ChooseControl to be nil, got %+v", lobby.ChooseControl)
+			}
+
+			sizes := make(map[int]int)
+			for _, team := range lobby.Teams {
+				sizes[team.ID] = len(team.Players)
+			}
+
+			expectedSizes := make(map[int]int)
+			for i := 0; i < c.teams; i++ {
+				expectedSizes[i] = 0
+			}
+			for _, name := range c.expected {
+				expectedSizes[c.teams-1-int(name-'A')]++
+			}
+
+			if !reflect.DeepEqual(sizes, expectedSizes) {
+				t.Errorf("Expected team sizes %v, got %v", expectedSizes, sizes)
+			}
+		})
+	}
+}
+
+func TestGetNextTeamLeaderToPick_DoesNotReorderTeams(t *testing.T) {
+	lobby := newDraftLobby(Snake, 3, 3)
+
+	ids := []int{lobby.Teams[0].ID, lobby.Teams[1].ID, lobby.Teams[2].ID}
+
+	_, _ = lobby.GetNextTeamLeaderToPick()
+
+	for i, team := range lobby.Teams {
+		if team.ID != ids[i] {
+			t.Errorf("Expected team %d at position %d, got %d", ids[i], i, team.ID)
+		}
+	}
+}
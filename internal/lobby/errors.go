@@ -0,0 +1,118 @@
+package lobby
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind groups domain errors by how the caller should react to them.
+type ErrorKind string
+
+const (
+	NotFound      ErrorKind = "not_found"
+	Conflict      ErrorKind = "conflict"
+	Unprocessable ErrorKind = "unprocessable"
+	Unauthorized  ErrorKind = "unauthorized"
+	Forbidden     ErrorKind = "forbidden"
+	Expired       ErrorKind = "expired"
+	Unavailable   ErrorKind = "unavailable"
+	Internal      ErrorKind = "internal"
+)
+
+// Error is a domain error with a stable machine readable code. Error()
+// returns the code, so clients and tests can branch on it.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Code
+}
+
+// Is matches errors by code, so errors.Is works on copies with details.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of the error carrying extra context.
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+func newError(kind ErrorKind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+var (
+	ErrLobbyNotFound           = newError(NotFound, "lobby_not_found", "lobby not found")
+	ErrProfileNotFound         = newError(NotFound, "profile_not_found", "profile not found")
+	ErrProfileNotInLobby       = newError(NotFound, "profile_not_in_lobby", "profile is not in the lobby")
+	ErrTeamNotFound            = newError(NotFound, "team_not_found", "team not found")
+	ErrInvalidStatus           = newError(Conflict, "invalid_status", "action is not allowed in the current lobby status")
+	ErrProfileAlreadyLeader    = newError(Conflict, "profile_is_already_leader", "profile is already a leader")
+	ErrProfileAlreadyInLobby   = newError(Conflict, "profile_already_in_lobby", "profile is already in the lobby")
+	ErrVersionConflict         = newError(Conflict, "lobby_version_conflict", "lobby was changed by another request, try again")
+	ErrTeamAlreadyTaken        = newError(Conflict, "team_already_taken", "team already has a leader")
+	ErrNothingToUndo           = newError(Conflict, "nothing_to_undo", "there is no selection to undo in the current phase")
+	ErrTurnNotExpired          = newError(Conflict, "turn_not_expired", "the current turn has not expired")
+	ErrAccessCodeTaken         = newError(Conflict, "access_code_taken", "another lobby already has the access code")
+	ErrNotEnoughPlayers        = newError(Unprocessable, "not_enough_players", "not enough players to create the teams")
+	ErrNotEnoughMentors        = newError(Unprocessable, "not_enough_mentors", "not enough mentors to create the teams")
+	ErrTooManySkills           = newError(Unprocessable, "profile_has_too_many_skills", "profile has more skills than the lobby allows")
+	ErrInvalidTeam             = newError(Unprocessable, "invalid_team", "team id is invalid")
+	ErrInvalidPlayer           = newError(Unprocessable, "invalid_player", "player is not available to be selected")
+	ErrInvalidDraftOrder       = newError(Unprocessable, "invalid_draft_order", "draft order must be round_robin, snake or reverse_priority")
+	ErrInvalidTeamFormation    = newError(Unprocessable, "invalid_team_formation", "team formation must be draft or balanced")
+	ErrInvalidPriorityStrategy = newError(Unprocessable, "invalid_priority_strategy", "priority strategy must be default, weighted or lottery")
+	ErrInvalidLeaderRule       = newError(Unprocessable, "invalid_leader_rule", "leader rule must be any_of or all_of with known skills, or nominated without skills")
+	ErrInvalidSkillCatalogue   = newError(Unprocessable, "invalid_skill_catalogue", "skill catalogue must have skills with unique ids and weights that are not negative")
+	ErrInvalidProfile          = newError(Unprocessable, "invalid_profile", "profile must have a name")
+	ErrInvalidSkillLevel       = newError(Unprocessable, "invalid_skill_level", "skill levels must be between 1 and 5 and given only for the skills of the profile")
+	ErrUnknownSkill            = newError(Unprocessable, "unknown_skill", "profile has skills that are not in the skill catalogue of the lobby")
+	ErrInvalidCapacity         = newError(Unprocessable, "invalid_capacity", "capacity limits must not be negative and the minimum team size must not exceed the maximum")
+	ErrTeamSizeUnsatisfiable   = newError(Unprocessable, "team_size_unsatisfiable", "players can not be split into the teams within the team size limits")
+	ErrInvalidAction           = newError(Unprocessable, "invalid_action", "action is unknown")
+	ErrInvalidAccessCodeLength = newError(Unprocessable, "invalid_access_code_length", "access codes must have at least 4 characters")
+	ErrInvalidPage             = newError(Unprocessable, "invalid_page", "page limit must be between 1 and 100 and the cursor one returned with a previous page")
+	ErrProfileSecretRequired   = newError(Unauthorized, "profile_secret_required", "the secret returned when the profile was created is required to use it")
+	ErrWrongProfileSecret      = newError(Forbidden, "wrong_profile_secret", "the secret does not match the profile")
+	ErrNotMaster               = newError(Forbidden, "profile_is_not_a_master", "profile is not a master")
+	ErrNotLeader               = newError(Forbidden, "profile_is_not_a_leader", "profile is not a leader")
+	ErrProfileKicked           = newError(Forbidden, "profile_was_kicked", "profile was removed from the lobby by the master")
+	ErrNotLeaderTurn           = newError(Forbidden, "not_leader_turn", "it is not the turn of the leader to choose")
+	ErrEventsExpired           = newError(Expired, "events_expired", "requested events are no longer available, reload the lobby")
+	ErrTooManySubscribers      = newError(Unavailable, "too_many_subscribers", "lobby reached the maximum number of subscribers")
+	ErrNextLeaderNotFound      = newError(Internal, "next_leader_not_found", "could not find the next leader to choose")
+	ErrInvalidEventLog         = newError(Internal, "invalid_event_log", "the events of the lobby can not be replayed")
+	ErrEventLogMismatch        = newError(Internal, "event_log_mismatch", "replaying the events of the lobby does not produce its current state")
+)
+
+func invalidStatus(status LobbyStatus) *Error {
+	return ErrInvalidStatus.WithDetails(map[string]interface{}{"status": status})
+}
+
+// VersionConflictError is returned by a LobbyRepository when the stored lobby
+// was changed after it was loaded, so the update was not applied.
+type VersionConflictError struct {
+	LobbyID string
+	Version int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("lobby_version_conflict: lobby %s is no longer at version %d", e.LobbyID, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+func IsVersionConflict(err error) bool {
+	var conflict *VersionConflictError
+	return errors.As(err, &conflict)
+}
@@ -0,0 +1,40 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestError_IsMatchesByCode(t *testing.T) {
+	err := invalidStatus(Waiting)
+
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected %v to match ErrInvalidStatus", err)
+	}
+
+	if errors.Is(err, ErrNotEnoughPlayers) {
+		t.Errorf("Expected %v not to match ErrNotEnoughPlayers", err)
+	}
+
+	if err.Details["status"] != Waiting {
+		t.Errorf("Expected status detail to be Waiting, got %v", err.Details["status"])
+	}
+
+	if ErrInvalidStatus.Details != nil {
+		t.Errorf("Expected WithDetails not to change the sentinel, got %v", ErrInvalidStatus.Details)
+	}
+}
+
+func TestVersionConflictError_UnwrapsToCatalogue(t *testing.T) {
+	err := error(&VersionConflictError{LobbyID: "id", Version: 1})
+
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
+		t.Errorf("Expected version conflict to unwrap to a domain error")
+		return
+	}
+
+	if domainErr.Kind != Conflict {
+		t.Errorf("Expected kind to be Conflict, got %v", domainErr.Kind)
+	}
+}
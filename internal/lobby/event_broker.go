@@ -0,0 +1,215 @@
+package lobby
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultEventHistorySize = 256
+	defaultMaxSubscribers   = 100
+	subscriptionBufferSize  = 64
+	defaultStreamIdleTTL    = 30 * time.Minute
+)
+
+// EventBroker fans lobby events out to the subscribers of each lobby and keeps
+// a bounded history per lobby so reconnecting clients can resume from the
+// last sequence number they received. The stream of a lobby is dropped once
+// nobody is subscribed to it and nothing was published for idleTTL, or as
+// soon as nobody is subscribed after Retire.
+type EventBroker struct {
+	mu             sync.Mutex
+	historySize    int
+	maxSubscribers int // per lobby
+	idleTTL        time.Duration
+	streams        map[string]*eventStream
+	lastSweep      time.Time
+	now            func() time.Time
+}
+
+type eventStream struct {
+	sequence    uint64
+	history     []LobbyEvent
+	subscribers map[*Subscription]struct{}
+	lastActive  time.Time
+	retired     bool
+}
+
+type Subscription struct {
+	Events <-chan LobbyEvent
+
+	events     chan LobbyEvent
+	broker     *EventBroker
+	accessCode string
+	closeOnce  sync.Once
+}
+
+func NewEventBroker(historySize int, maxSubscribers int, idleTTL time.Duration) *EventBroker {
+	if historySize <= 0 {
+		historySize = defaultEventHistorySize
+	}
+
+	if maxSubscribers <= 0 {
+		maxSubscribers = defaultMaxSubscribers
+	}
+
+	if idleTTL <= 0 {
+		idleTTL = defaultStreamIdleTTL
+	}
+
+	return &EventBroker{
+		historySize:    historySize,
+		maxSubscribers: maxSubscribers,
+		idleTTL:        idleTTL,
+		streams:        make(map[string]*eventStream),
+		lastSweep:      time.Now(),
+		now:            time.Now,
+	}
+}
+
+func (b *EventBroker) Publish(accessCode string, events ...LobbyEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.stream(accessCode)
+
+	for _, event := range events {
+		stream.sequence++
+		event.Sequence = stream.sequence
+		event.AccessCode = accessCode
+
+		stream.history = append(stream.history, event)
+		if len(stream.history) > b.historySize {
+			stream.history = stream.history[len(stream.history)-b.historySize:]
+		}
+
+		for subscription := range stream.subscribers {
+			select {
+			case subscription.events <- event:
+			default: // slow consumer, it has to reconnect and resume
+				delete(stream.subscribers, subscription)
+				subscription.closeChannel()
+			}
+		}
+	}
+}
+
+// Subscribe registers a subscription for the lobby and returns the events
+// published after the since sequence number. A since of 0 means only new
+// events are wanted. ErrEventsExpired is returned when some of the requested
+// events are no longer in the history, in which case the client has to
+// reload the lobby before subscribing again. ErrTooManySubscribers is
+// returned once the lobby reached the subscriber cap.
+func (b *EventBroker) Subscribe(accessCode string, since uint64) (*Subscription, []LobbyEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.stream(accessCode)
+
+	if len(stream.subscribers) >= b.maxSubscribers {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	missed := make([]LobbyEvent, 0)
+	if since > 0 {
+		if since > stream.sequence {
+			return nil, nil, ErrEventsExpired
+		}
+
+		if since < stream.sequence && (len(stream.history) == 0 || stream.history[0].Sequence > since+1) {
+			return nil, nil, ErrEventsExpired
+		}
+
+		for _, event := range stream.history {
+			if event.Sequence > since {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	events := make(chan LobbyEvent, subscriptionBufferSize)
+	subscription := &Subscription{
+		Events:     events,
+		events:     events,
+		broker:     b,
+		accessCode: accessCode,
+	}
+
+	stream.subscribers[subscription] = struct{}{}
+	return subscription, missed, nil
+}
+
+// Retire drops the stream of a lobby that will publish no more events of
+// interest, such as a finished one, once its last subscriber leaves.
+func (b *EventBroker) Retire(accessCode string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream, ok := b.streams[accessCode]
+	if !ok {
+		return
+	}
+
+	stream.retired = true
+	b.dropUnsubscribed(accessCode, stream)
+}
+
+// stream returns the stream of the lobby, creating it if needed, and marks it
+// as active. Idle streams of other lobbies are swept at most once per idleTTL.
+func (b *EventBroker) stream(accessCode string) *eventStream {
+	now := b.now()
+	if now.Sub(b.lastSweep) >= b.idleTTL {
+		b.sweep(now)
+	}
+
+	stream, ok := b.streams[accessCode]
+	if !ok {
+		stream = &eventStream{
+			subscribers: make(map[*Subscription]struct{}),
+		}
+		b.streams[accessCode] = stream
+	}
+
+	stream.lastActive = now
+	return stream
+}
+
+func (b *EventBroker) sweep(now time.Time) {
+	b.lastSweep = now
+
+	for accessCode, stream := range b.streams {
+		if len(stream.subscribers) == 0 && now.Sub(stream.lastActive) >= b.idleTTL {
+			delete(b.streams, accessCode)
+		}
+	}
+}
+
+func (b *EventBroker) dropUnsubscribed(accessCode string, stream *eventStream) {
+	if stream.retired && len(stream.subscribers) == 0 {
+		delete(b.streams, accessCode)
+	}
+}
+
+// Close removes the subscription from the broker and closes its channel.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if stream, ok := s.broker.streams[s.accessCode]; ok {
+		delete(stream.subscribers, s)
+		stream.lastActive = s.broker.now()
+		s.broker.dropUnsubscribed(s.accessCode, stream)
+	}
+
+	s.closeChannel()
+}
+
+func (s *Subscription) closeChannel() {
+	s.closeOnce.Do(func() {
+		close(s.events)
+	})
+}
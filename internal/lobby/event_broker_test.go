@@ -0,0 +1,167 @@
+package lobby
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventBroker_PublishToSubscribers(t *testing.T) {
+	broker := NewEventBroker(10, 10, time.Minute)
+
+	subscription, missed, err := broker.Subscribe("abc123", 0)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	if len(missed) != 0 {
+		t.Errorf("Expected no missed events, got %d", len(missed))
+	}
+
+	broker.Publish("abc123", LobbyEvent{Type: PlayerJoined}, LobbyEvent{Type: TurnChanged})
+
+	first := <-subscription.Events
+	second := <-subscription.Events
+
+	if first.Sequence != 1 || first.Type != PlayerJoined {
+		t.Errorf("Expected first event to be player_joined with sequence 1, got %+v", first)
+	}
+
+	if second.Sequence != 2 || second.Type != TurnChanged {
+		t.Errorf("Expected second event to be turn_changed with sequence 2, got %+v", second)
+	}
+
+	subscription.Close()
+
+	if _, ok := <-subscription.Events; ok {
+		t.Errorf("Expected events channel to be closed")
+	}
+}
+
+func TestEventBroker_ResumeFromSequence(t *testing.T) {
+	broker := NewEventBroker(10, 10, time.Minute)
+
+	broker.Publish("abc123", LobbyEvent{Type: PlayerJoined}, LobbyEvent{Type: MentorJoined}, LobbyEvent{Type: StatusChanged})
+
+	subscription, missed, err := broker.Subscribe("abc123", 1)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	defer subscription.Close()
+
+	if len(missed) != 2 {
+		t.Errorf("Expected 2 missed events, got %d", len(missed))
+		return
+	}
+
+	if missed[0].Type != MentorJoined || missed[1].Type != StatusChanged {
+		t.Errorf("Expected mentor_joined and status_changed, got %+v", missed)
+	}
+}
+
+func TestEventBroker_ResumeWhenHistoryExpired(t *testing.T) {
+	broker := NewEventBroker(2, 10, time.Minute)
+
+	broker.Publish("abc123", LobbyEvent{Type: PlayerJoined}, LobbyEvent{Type: MentorJoined}, LobbyEvent{Type: StatusChanged}, LobbyEvent{Type: TurnChanged})
+
+	_, _, err := broker.Subscribe("abc123", 1)
+
+	if err != ErrEventsExpired {
+		t.Errorf("Expected ErrEventsExpired, got %v", err)
+	}
+
+	_, _, err = broker.Subscribe("abc123", 10)
+
+	if err != ErrEventsExpired {
+		t.Errorf("Expected ErrEventsExpired for unknown sequence, got %v", err)
+	}
+}
+
+func TestEventBroker_SubscriberCap(t *testing.T) {
+	broker := NewEventBroker(10, 1, time.Minute)
+
+	subscription, _, err := broker.Subscribe("abc123", 0)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	_, _, err = broker.Subscribe("abc123", 0)
+
+	if err != ErrTooManySubscribers {
+		t.Errorf("Expected ErrTooManySubscribers, got %v", err)
+	}
+
+	subscription.Close()
+
+	other, _, err := broker.Subscribe("abc123", 0)
+	if err != nil {
+		t.Errorf("Expected subscription after close, got %v", err)
+		return
+	}
+
+	other.Close()
+}
+
+func TestEventBroker_EvictIdleStreams(t *testing.T) {
+	broker := NewEventBroker(10, 10, time.Minute)
+	now := time.Now()
+	broker.now = func() time.Time { return now }
+
+	broker.Publish("idle", LobbyEvent{Type: PlayerJoined})
+
+	subscription, _, err := broker.Subscribe("watched", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer subscription.Close()
+
+	now = now.Add(2 * time.Minute)
+	broker.Publish("active", LobbyEvent{Type: PlayerJoined})
+
+	if _, ok := broker.streams["idle"]; ok {
+		t.Errorf("Expected the idle stream to be evicted")
+	}
+
+	if _, ok := broker.streams["watched"]; !ok {
+		t.Errorf("Expected the stream with a subscriber to be kept")
+	}
+
+	if _, ok := broker.streams["active"]; !ok {
+		t.Errorf("Expected the stream just published to be kept")
+	}
+}
+
+func TestEventBroker_Retire(t *testing.T) {
+	broker := NewEventBroker(10, 10, time.Minute)
+
+	broker.Publish("unwatched", LobbyEvent{Type: StatusChanged})
+	broker.Retire("unwatched")
+
+	if _, ok := broker.streams["unwatched"]; ok {
+		t.Errorf("Expected the retired stream without subscribers to be evicted")
+	}
+
+	subscription, _, err := broker.Subscribe("watched", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	broker.Publish("watched", LobbyEvent{Type: StatusChanged})
+	broker.Retire("watched")
+
+	if event := <-subscription.Events; event.Type != StatusChanged {
+		t.Errorf("Expected the subscriber to still receive status_changed, got %+v", event)
+	}
+
+	if _, ok := broker.streams["watched"]; !ok {
+		t.Errorf("Expected the retired stream to be kept while subscribed")
+	}
+
+	subscription.Close()
+
+	if _, ok := broker.streams["watched"]; ok {
+		t.Errorf("Expected the retired stream to be evicted after the last subscriber left")
+	}
+}
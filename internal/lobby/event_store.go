@@ -0,0 +1,14 @@
+package lobby
+
+import "context"
+
+// LobbyEventStore is the append-only log of the events of every lobby, the
+// audit trail of who changed what and when. Stored events are never changed
+// nor removed.
+type LobbyEventStore interface {
+	// Append stores the events after the ones already stored for their lobby,
+	// skipping the ones already stored, which have the same Version and Index.
+	Append(ctx context.Context, events ...LobbyEvent) error
+	// Load returns the events of the lobby in the order they were appended.
+	Load(ctx context.Context, accessCode string) ([]LobbyEvent, error)
+}
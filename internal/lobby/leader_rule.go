@@ -0,0 +1,133 @@
+package lobby
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+type LeaderRuleMode string
+
+const (
+	AnyOfSkills   LeaderRuleMode = "any_of"    // players with at least one of the skills become leaders
+	AllOfSkills   LeaderRuleMode = "all_of"    // players with every one of the skills become leaders
+	NominatedOnly LeaderRuleMode = "nominated" // nobody becomes a leader on Join, the Master promotes them
+)
+
+// LeaderRule decides which players become leaders when they join the lobby.
+// The other players stay players until the Master promotes them.
+type LeaderRule struct {
+	Mode       LeaderRuleMode      `json:"mode"`
+	HardSkills []profile.HardSkill `json:"hard_skills,omitempty"`
+	SoftSkills []profile.SoftSkill `json:"soft_skills,omitempty"`
+}
+
+// DefaultLeaderRule makes leaders of the players with GDP or Leadership.
+func DefaultLeaderRule() LeaderRule {
+	return LeaderRule{
+		Mode:       AnyOfSkills,
+		HardSkills: []profile.HardSkill{profile.GDP},
+		SoftSkills: []profile.SoftSkill{profile.Leadership},
+	}
+}
+
+// DefaultLeaderRuleFor is the default rule limited to the skills of the
+// catalogue, which are the only ones players can have. When the catalogue has
+// neither GDP nor Leadership nobody becomes a leader on joining and the Master
+// nominates the leaders.
+func DefaultLeaderRuleFor(catalogue SkillCatalogue) LeaderRule {
+	rule := LeaderRule{Mode: AnyOfSkills}
+	defaults := DefaultLeaderRule()
+
+	for _, skill := range defaults.HardSkills {
+		if catalogue.HasHardSkill(skill) {
+			rule.HardSkills = append(rule.HardSkills, skill)
+		}
+	}
+
+	for _, skill := range defaults.SoftSkills {
+		if catalogue.HasSoftSkill(skill) {
+			rule.SoftSkills = append(rule.SoftSkills, skill)
+		}
+	}
+
+	if len(rule.HardSkills)+len(rule.SoftSkills) == 0 {
+		return LeaderRule{Mode: NominatedOnly}
+	}
+
+	return rule
+}
+
+// Validate checks the mode and that every skill of the rule is in the
+// catalogue of the lobby.
+func (r LeaderRule) Validate(catalogue SkillCatalogue) error {
+	details := map[string]interface{}{
+		"mode":        r.Mode,
+		"hard_skills": r.HardSkills,
+		"soft_skills": r.SoftSkills,
+	}
+
+	switch r.Mode {
+	case AnyOfSkills, AllOfSkills:
+		if len(r.HardSkills)+len(r.SoftSkills) == 0 {
+			return ErrInvalidLeaderRule.WithDetails(details)
+		}
+	case NominatedOnly:
+		if len(r.HardSkills)+len(r.SoftSkills) > 0 {
+			return ErrInvalidLeaderRule.WithDetails(details)
+		}
+	default:
+		return ErrInvalidLeaderRule.WithDetails(details)
+	}
+
+	for _, skill := range r.HardSkills {
+		if !catalogue.HasHardSkill(skill) {
+			return ErrInvalidLeaderRule.WithDetails(details)
+		}
+	}
+
+	for _, skill := range r.SoftSkills {
+		if !catalogue.HasSoftSkill(skill) {
+			return ErrInvalidLeaderRule.WithDetails(details)
+		}
+	}
+
+	return nil
+}
+
+// Eligible reports whether the player becomes a leader on joining.
+func (r LeaderRule) Eligible(p profile.Profile) bool {
+	if r.Mode == NominatedOnly {
+		return false
+	}
+
+	matches := 0
+	for _, skill := range r.HardSkills {
+		if p.HasHardSkill(skill) {
+			matches++
+		}
+	}
+
+	for _, skill := range r.SoftSkills {
+		if p.HasSoftSkill(skill) {
+			matches++
+		}
+	}
+
+	if r.Mode == AllOfSkills {
+		return matches == len(r.HardSkills)+len(r.SoftSkills)
+	}
+
+	return matches > 0
+}
+
+// WithLeaderRule sets which players become leaders when they join.
+func WithLeaderRule(rule LeaderRule) LobbyOption {
+	return func(l *Lobby) {
+		l.LeaderRule = rule
+	}
+}
+
+func (l *Lobby) leaderRule() LeaderRule {
+	if l.LeaderRule.Mode == "" { // lobbies created before leader rules existed
+		return DefaultLeaderRule()
+	}
+
+	return l.LeaderRule
+}
@@ -0,0 +1,108 @@
+package lobby
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestLeaderRule_Validate(t *testing.T) {
+	cases := []struct {
+		rule  LeaderRule
+		valid bool
+	}{
+		{DefaultLeaderRule(), true},
+		{LeaderRule{Mode: AllOfSkills, SoftSkills: []profile.SoftSkill{profile.Leadership, profile.Communication}}, true},
+		{LeaderRule{Mode: NominatedOnly}, true},
+		{LeaderRule{Mode: AnyOfSkills}, false},
+		{LeaderRule{Mode: NominatedOnly, HardSkills: []profile.HardSkill{profile.GDP}}, false},
+		{LeaderRule{Mode: AnyOfSkills, HardSkills: []profile.HardSkill{"Cooking"}}, false},
+		{LeaderRule{Mode: "most_of", HardSkills: []profile.HardSkill{profile.GDP}}, false},
+	}
+
+	for _, c := range cases {
+		err := c.rule.Validate(DefaultSkillCatalogue())
+
+		if c.valid && err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", c.rule, err)
+		}
+
+		if !c.valid && !errors.Is(err, ErrInvalidLeaderRule) {
+			t.Errorf("Expected ErrInvalidLeaderRule for %+v, got %v", c.rule, err)
+		}
+	}
+}
+
+func TestJoin_LeaderRules(t *testing.T) {
+	leadership := profile.NewPlayer("Leadership", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership})
+	both := profile.NewPlayer("Both", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership, profile.Communication})
+	organizer := profile.NewPlayer("Organizer", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Organization})
+
+	cases := []struct {
+		name     string
+		rule     LeaderRule
+		expected map[string]profile.Role
+	}{
+		{"default", DefaultLeaderRule(), map[string]profile.Role{"Leadership": profile.Leader, "Both": profile.Leader, "Organizer": profile.Player}},
+		{"all of", LeaderRule{Mode: AllOfSkills, SoftSkills: []profile.SoftSkill{profile.Leadership, profile.Communication}}, map[string]profile.Role{"Leadership": profile.Player, "Both": profile.Leader, "Organizer": profile.Player}},
+		{"any of", LeaderRule{Mode: AnyOfSkills, HardSkills: []profile.HardSkill{profile.GDP}, SoftSkills: []profile.SoftSkill{profile.Organization}}, map[string]profile.Role{"Leadership": profile.Player, "Both": profile.Player, "Organizer": profile.Leader}},
+		{"nominated", LeaderRule{Mode: NominatedOnly}, map[string]profile.Role{"Leadership": profile.Player, "Both": profile.Player, "Organizer": profile.Player}},
+	}
+
+	for _, c := range cases {
+		lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Rules Lobby", 1, 2, WithLeaderRule(c.rule))
+		for _, p := range []profile.Profile{leadership, both, organizer} {
+			_ = lobby.Join(p)
+		}
+
+		for _, p := range lobby.Players {
+			if p.Role != c.expected[p.Name] {
+				t.Errorf("%s: expected %s to be %v, got %v", c.name, p.Name, c.expected[p.Name], p.Role)
+			}
+		}
+	}
+}
+
+func TestJoin_NominatedOnly_GoesToLeaderElection(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Rules Lobby", 1, 2, WithLeaderRule(LeaderRule{Mode: NominatedOnly}))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewPlayer("Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership}))
+	_ = lobby.Join(newPlayer("Player"))
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+
+	if lobby.Status != LeaderElection {
+		t.Errorf("Expected the Master to nominate the leaders in %v, got %v", LeaderElection, lobby.Status)
+	}
+}
+
+func TestDefaultLeaderRuleFor(t *testing.T) {
+	storytelling := SkillDefinition{ID: "Storytelling"}
+	sql := SkillDefinition{ID: "SQL"}
+
+	cases := []struct {
+		name      string
+		catalogue SkillCatalogue
+		expected  LeaderRule
+	}{
+		{"default catalogue", DefaultSkillCatalogue(), DefaultLeaderRule()},
+		{"only Leadership", SkillCatalogue{HardSkills: []SkillDefinition{sql}, SoftSkills: []SkillDefinition{{ID: string(profile.Leadership)}, storytelling}},
+			LeaderRule{Mode: AnyOfSkills, SoftSkills: []profile.SoftSkill{profile.Leadership}}},
+		{"custom catalogue", SkillCatalogue{HardSkills: []SkillDefinition{sql}, SoftSkills: []SkillDefinition{storytelling}}, LeaderRule{Mode: NominatedOnly}},
+	}
+
+	for _, c := range cases {
+		rule := DefaultLeaderRuleFor(c.catalogue)
+
+		if err := rule.Validate(c.catalogue); err != nil {
+			t.Errorf("%s: expected the rule to fit the catalogue, got %v", c.name, err)
+		}
+
+		if !reflect.DeepEqual(rule, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, rule)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package lobby
+
+// Statuses of the RPG session, after the teams are ready.
+const (
+	InProgress LobbyStatus = "InProgress"
+	Paused     LobbyStatus = "Paused"
+	Finished   LobbyStatus = "Finished"
+	Archived   LobbyStatus = "Archived"
+)
+
+// lifecycleActions are the actions that only move the session from one
+// status to another.
+var lifecycleActions = map[LobbyAction]bool{
+	StartAction:   true,
+	PauseAction:   true,
+	ResumeAction:  true,
+	FinishAction:  true,
+	ArchiveAction: true,
+}
+
+func ParseLobbyAction(value string) (LobbyAction, error) {
+	action := LobbyAction(value)
+	if !lifecycleActions[action] {
+		return "", ErrInvalidAction.WithDetails(map[string]interface{}{"action": value})
+	}
+
+	return action, nil
+}
+
+// Apply moves the session to the status the transition table gives for a
+// lifecycle action.
+func (l *Lobby) Apply(action LobbyAction) error {
+	if !lifecycleActions[action] {
+		return ErrInvalidAction.WithDetails(map[string]interface{}{"action": action})
+	}
+
+	t, err := l.transition(action)
+	if err != nil {
+		return err
+	}
+
+	l.setStatus(t.next[0])
+	return nil
+}
@@ -0,0 +1,88 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestApply_Lifecycle(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+	lobby.Status = ReadyToStart
+
+	steps := []struct {
+		action   LobbyAction
+		expected LobbyStatus
+	}{
+		{StartAction, InProgress},
+		{PauseAction, Paused},
+		{ResumeAction, InProgress},
+		{FinishAction, Finished},
+		{ArchiveAction, Archived},
+	}
+
+	for _, step := range steps {
+		err := lobby.Apply(step.action)
+
+		if err != nil {
+			t.Fatalf("Expected no error on %v, got %v", step.action, err)
+		}
+
+		if lobby.Status != step.expected {
+			t.Errorf("Expected Status to be %v after %v, got %v", step.expected, step.action, lobby.Status)
+		}
+
+		if lobby.StatusTimestamps[step.expected] == 0 {
+			t.Errorf("Expected a timestamp for %v", step.expected)
+		}
+	}
+
+	if lobby.StatusTimestamps[Waiting] == 0 {
+		t.Errorf("Expected a timestamp for Waiting")
+	}
+}
+
+func TestApply_WhenTransitionIsInvalid(t *testing.T) {
+	cases := []struct {
+		status LobbyStatus
+		action LobbyAction
+	}{
+		{Waiting, StartAction},
+		{PlayerSelect, FinishAction},
+		{ReadyToStart, PauseAction},
+		{InProgress, ResumeAction},
+		{InProgress, ArchiveAction},
+		{Paused, PauseAction},
+		{Archived, StartAction},
+	}
+
+	for _, c := range cases {
+		lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+		lobby.Status = c.status
+
+		err := lobby.Apply(c.action)
+
+		if !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("Expected ErrInvalidStatus on %v from %v, got %v", c.action, c.status, err)
+		}
+
+		if lobby.Status != c.status {
+			t.Errorf("Expected Status to stay %v, got %v", c.status, lobby.Status)
+		}
+	}
+}
+
+func TestParseLobbyAction(t *testing.T) {
+	action, err := ParseLobbyAction("pause")
+
+	if err != nil || action != PauseAction {
+		t.Errorf("Expected PauseAction, got %v and %v", action, err)
+	}
+
+	_, err = ParseLobbyAction("restart")
+
+	if !errors.Is(err, ErrInvalidAction) {
+		t.Errorf("Expected ErrInvalidAction, got %v", err)
+	}
+}
@@ -1,8 +1,8 @@
 package lobby
 
 import (
-	"errors"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
@@ -27,11 +27,28 @@ type Lobby struct {
 	Name          string
 	MaxHardSkills int
 	MaxSoftSkills int
-	Players       []profile.Profile
-	Mentors       []profile.Profile
-	Teams         []*Team
-	Status        LobbyStatus
-	ChooseControl *ChooseControl
+	Capacity
+	Players          []profile.Profile
+	Mentors          []profile.Profile
+	Departed         []profile.Profile // left the lobby and can rejoin
+	Kicked           []string          // IDs of the profiles removed by the Master
+	Waitlist         []profile.Profile // joined while the lobby was full, in order of arrival
+	Teams            []*Team
+	Status           LobbyStatus
+	StatusTimestamps map[LobbyStatus]int64 // unix timestamp of when the lobby last entered each status
+	ChooseControl    *ChooseControl
+	DraftHistory     []DraftAction // selections of the draft, the most recent last
+	TurnTimeout      time.Duration // 0 means leaders have no time limit to choose
+	DraftOrder       DraftOrder
+	TeamFormation    TeamFormation
+	PriorityStrategy PriorityStrategy
+	LeaderRule       LeaderRule
+	SkillCatalogue   SkillCatalogue
+	CreatedAt        int64        // unix timestamp
+	Version          int          // incremented by the repository on every successful update
+	Outbox           []LobbyEvent // stored with the lobby until appended to the event log
+
+	events []LobbyEvent // recorded changes not yet published
 }
 
 type ChooseType string
@@ -45,17 +62,12 @@ const (
 type ChooseControl struct {
 	ChoosingNow profile.Profile
 	Type        ChooseType
+	Deadline    int64 // unix timestamp, 0 when the turn has no time limit
 }
 
-const (
-	LeadershipWeight    = 10
-	GDPWeight           = 100
-	ElectedLeaderWeight = 1000
-)
-
 func NewPromoteLeaderChooseControl(p profile.Profile) (*ChooseControl, error) {
 	if p.Role != profile.Master {
-		return nil, errors.New("profile is not a master")
+		return nil, ErrNotMaster
 	}
 
 	return &ChooseControl{
@@ -66,7 +78,7 @@ func NewPromoteLeaderChooseControl(p profile.Profile) (*ChooseControl, error) {
 
 func NewSelectTeamChooseControl(p profile.Profile) (*ChooseControl, error) {
 	if p.Role != profile.Leader {
-		return nil, errors.New("profile is not a leader")
+		return nil, ErrNotLeader
 	}
 
 	return &ChooseControl{
@@ -77,7 +89,7 @@ func NewSelectTeamChooseControl(p profile.Profile) (*ChooseControl, error) {
 
 func NewSelectPlayerChooseControl(p profile.Profile) (*ChooseControl, error) {
 	if p.Role != profile.Leader {
-		return nil, errors.New("profile is not a leader")
+		return nil, ErrNotLeader
 	}
 
 	return &ChooseControl{
@@ -86,108 +98,237 @@ func NewSelectPlayerChooseControl(p profile.Profile) (*ChooseControl, error) {
 	}, nil
 }
 
-func NewLobby(master profile.Profile, name string, maxHardSkills int, maxSoftSkills int) *Lobby {
+// LobbyOption configures optional settings of a lobby at creation.
+type LobbyOption func(l *Lobby)
+
+// WithTurnTimeout limits how long a leader has to choose a team or a player
+// before a choice is made automatically.
+func WithTurnTimeout(timeout time.Duration) LobbyOption {
+	return func(l *Lobby) {
+		l.TurnTimeout = timeout
+	}
+}
+
+func NewLobby(master profile.Profile, name string, maxHardSkills int, maxSoftSkills int, opts ...LobbyOption) *Lobby {
 	if master.Role != profile.Master {
 		panic("master is not a master")
 	}
 
 	id := uuid.New().String()
 
-	return &Lobby{
-		ID:            id,
-		AccessCode:    id[:6],
-		Master:        master,
-		Status:        Waiting,
-		Name:          name,
-		MaxHardSkills: maxHardSkills,
-		MaxSoftSkills: maxSoftSkills,
-		Players:       []profile.Profile{},
-		Mentors:       []profile.Profile{},
-	}
+	lobby := &Lobby{
+		ID:               id,
+		AccessCode:       DefaultAccessCodeGenerator().Generate(),
+		Master:           master,
+		Status:           Waiting,
+		Name:             name,
+		MaxHardSkills:    maxHardSkills,
+		MaxSoftSkills:    maxSoftSkills,
+		Players:          []profile.Profile{},
+		Mentors:          []profile.Profile{},
+		DraftOrder:       RoundRobin,
+		TeamFormation:    DraftFormation,
+		PriorityStrategy: DefaultPriority{},
+		LeaderRule:       DefaultLeaderRule(),
+		SkillCatalogue:   DefaultSkillCatalogue(),
+	}
+
+	for _, opt := range opts {
+		opt(lobby)
+	}
+
+	at := lobby.emit(LobbyCreated, LobbyCreatedPayload{
+		ID:                lobby.ID,
+		Name:              lobby.Name,
+		Master:            ResponseFromProfile(&lobby.Master),
+		MaxHardSkills:     lobby.MaxHardSkills,
+		MaxSoftSkills:     lobby.MaxSoftSkills,
+		MaxPlayers:        lobby.MaxPlayers,
+		MaxMentors:        lobby.MaxMentors,
+		MinPlayersPerTeam: lobby.MinPlayersPerTeam,
+		MaxPlayersPerTeam: lobby.MaxPlayersPerTeam,
+		TurnTimeout:       lobby.TurnTimeout,
+		DraftOrder:        lobby.DraftOrder,
+		TeamFormation:     lobby.TeamFormation,
+		PriorityStrategy:  lobby.priorityStrategy().Settings(),
+		LeaderRule:        lobby.leaderRule(),
+		SkillCatalogue:    lobby.skillCatalogue(),
+	})
+	lobby.CreatedAt = at
+	lobby.StatusTimestamps = map[LobbyStatus]int64{Waiting: at}
+
+	return lobby
 }
 
 func (l *Lobby) Join(p profile.Profile) error {
-	if l.Status != Waiting {
-		return errors.New("invalid_status")
+	if err := l.Can(JoinAction, p); err != nil {
+		return err
 	}
 
 	if p.Role == profile.Mentor {
+		if !l.hasRoomFor(p) {
+			l.addToWaitlist(p)
+			return nil
+		}
+
 		l.Mentors = append(l.Mentors, p)
+		l.emit(MentorJoined, ResponseFromProfile(&p))
 		return nil
 	}
 
-	if len(p.HardSkills) > l.MaxHardSkills || len(p.SoftSkills) > l.MaxSoftSkills {
-		return errors.New("profile has too many skills")
+	if p.CountHardSkills() > l.MaxHardSkills || p.CountSoftSkills() > l.MaxSoftSkills {
+		return ErrTooManySkills.WithDetails(map[string]interface{}{
+			"max_hard_skills": l.MaxHardSkills,
+			"max_soft_skills": l.MaxSoftSkills,
+		})
+	}
+
+	if err := l.skillCatalogue().checkProfile(p); err != nil {
+		return err
+	}
+
+	if err := checkSkillLevels(p); err != nil {
+		return err
+	}
+
+	p.FillSkillLevels()
+
+	p.Role = profile.Player
+	if l.leaderRule().Eligible(p) {
+		p.Role = profile.Leader
 	}
 
 	p.Join()
+	if !l.hasRoomFor(p) {
+		l.addToWaitlist(p)
+		return nil
+	}
+
 	l.Players = append(l.Players, p)
+	l.emit(PlayerJoined, ResponseFromProfile(&p))
+	return nil
+}
+
+// checkSkillLevels returns ErrInvalidSkillLevel when a level is out of range
+// or given for a skill the profile does not have.
+func checkSkillLevels(p profile.Profile) error {
+	for skill, proficiency := range p.HardSkillLevels {
+		if !p.HasHardSkill(skill) || !proficiency.Level.Valid() {
+			return ErrInvalidSkillLevel.WithDetails(map[string]interface{}{"hard_skill": skill, "level": proficiency.Level})
+		}
+	}
+
+	for skill, proficiency := range p.SoftSkillLevels {
+		if !p.HasSoftSkill(skill) || !proficiency.Level.Valid() {
+			return ErrInvalidSkillLevel.WithDetails(map[string]interface{}{"soft_skill": skill, "level": proficiency.Level})
+		}
+	}
+
+	return nil
+}
+
+// EnsureMaster returns ErrNotMaster unless p is the master of the lobby.
+func (l *Lobby) EnsureMaster(p profile.Profile) error {
+	if p.ID != l.Master.ID {
+		return ErrNotMaster
+	}
+
 	return nil
 }
 
 func (l *Lobby) StartTeamCreation() error {
-	if l.Status != Waiting {
-		return errors.New("invalid_status")
+	if _, err := l.transition(CloseAction); err != nil {
+		return err
 	}
 
 	if len(l.Players) < 2 {
-		return errors.New("not_enough_players")
+		return ErrNotEnoughPlayers
 	}
 
 	if len(l.Mentors) == 0 {
-		return errors.New("not_enough_mentors")
+		return ErrNotEnoughMentors
 	}
 
-	l.Status = CreatingTeam
+	if err := l.checkTeamSizes(); err != nil {
+		return err
+	}
+
+	l.dismissWaitlist()
+	l.setStatus(CreatingTeam)
+	return nil
+}
+
+// CancelTeamCreation reopens the lobby when the teams could not be created.
+func (l *Lobby) CancelTeamCreation() error {
+	if _, err := l.transition(CancelTeamCreationAction); err != nil {
+		return err
+	}
+
+	l.setStatus(Waiting)
 	return nil
 }
 
 func (l *Lobby) CreateTeams() error {
-	if l.Status != CreatingTeam {
-		return errors.New("invalid_status")
+	if _, err := l.transition(CreateTeamsAction); err != nil {
+		return err
 	}
 
 	if !l.hasSufficienteLeaders() {
-		l.Status = LeaderElection
+		l.setStatus(LeaderElection)
 		chooseControl, err := NewPromoteLeaderChooseControl(l.Master)
 
 		if err != nil {
 			return err
 		}
 
-		l.ChooseControl = chooseControl
+		l.setChooseControl(chooseControl)
 		return nil
 	}
 
+	l.buildTeams()
+	l.setStatus(TeamsCreated)
+	return nil
+}
+
+// buildTeams creates one team per mentor.
+func (l *Lobby) buildTeams() {
+	payload := TeamsBuiltPayload{Teams: make([]TeamResponse, 0, len(l.Mentors))}
+
 	for i, mentor := range l.Mentors {
 		team := NewTeam(i, mentor)
 		l.Teams = append(l.Teams, &team)
+		payload.Teams = append(payload.Teams, ResponseFromTeam(&team))
 	}
 
-	l.Status = TeamsCreated
-	return nil
+	l.emit(TeamsBuilt, payload)
 }
 
 func (l *Lobby) PromoteLeader(p profile.Profile) error {
-	if l.Status != LeaderElection {
-		return errors.New("invalid_status")
+	if _, err := l.transition(PromoteAction); err != nil {
+		return err
 	}
 
 	player := l.getPlayer(p.ID)
 
+	if player == nil {
+		return ErrProfileNotInLobby
+	}
+
 	if player.Role == profile.Leader {
-		return errors.New("profile_is_already_leader")
+		return ErrProfileAlreadyLeader
 	}
 
 	for i, profile_ := range l.Players {
 		if profile_.ID == player.ID {
 			l.Players[i].Role = profile.Leader
+			l.emit(LeaderPromoted, ResponseFromProfile(&l.Players[i]))
 			break
 		}
 	}
 
 	if l.hasSufficienteLeaders() {
-		l.Status = TeamsCreated
+		l.buildTeams()
+		l.setStatus(TeamsCreated)
 		l.ChooseControl = nil
 	}
 
@@ -195,12 +336,12 @@ func (l *Lobby) PromoteLeader(p profile.Profile) error {
 }
 
 func (l *Lobby) StartLeaderTeamSelection() error {
-	if l.Status != TeamsCreated {
-		return errors.New("lobby is not in TeamsCreated status")
+	if _, err := l.transition(StartTeamSelectionAction); err != nil {
+		return err
 	}
 
 	l.DefinePriorities()
-	l.Status = LeaderTeamSelect
+	l.setStatus(LeaderTeamSelect)
 
 	leader, err := l.GetNextLeader()
 
@@ -213,17 +354,15 @@ func (l *Lobby) StartLeaderTeamSelection() error {
 		return err
 	}
 
-	l.ChooseControl = chooseControl
+	l.setChooseControl(chooseControl)
 	return nil
 }
 
 func (l *Lobby) GetNextLeader() (*profile.Profile, error) {
-	sort.SliceStable(l.Players, func(i, j int) bool {
-		return l.Players[i].SelectionPriority < l.Players[j].SelectionPriority
-	})
+	sortByPriority(l.Players)
 
 	if l.Status != LeaderTeamSelect {
-		return nil, errors.New("lobby is not in LeaderTeamSelect or PlayerSelect status")
+		return nil, invalidStatus(l.Status)
 	}
 
 	lastPriority := -1
@@ -240,27 +379,22 @@ func (l *Lobby) GetNextLeader() (*profile.Profile, error) {
 	return nil, nil
 }
 
+// GetNextTeamLeaderToPick returns the leader whose team picks next, following
+// the draft order of the lobby and the number of players already picked.
 func (l *Lobby) GetNextTeamLeaderToPick() (*profile.Profile, error) {
-	sort.SliceStable(l.Teams, func(i, j int) bool {
-		return l.Teams[i].Leader.SelectionPriority < l.Teams[j].Leader.SelectionPriority
-	})
-
 	if l.Status != PlayerSelect {
-		return nil, errors.New("lobby is not in LeaderTeamSelect or PlayerSelect status")
+		return nil, invalidStatus(l.Status)
 	}
 
-	lastPriority := -1
-	if l.ChooseControl != nil {
-		lastPriority = l.ChooseControl.ChoosingNow.SelectionPriority
+	leaders := l.leadersByPriority()
+	if len(leaders) == 0 {
+		return nil, nil
 	}
 
-	for _, t := range l.Teams {
-		if t.Leader.SelectionPriority > lastPriority {
-			return &t.Leader, nil
-		}
-	}
+	picks := l.pickCount()
+	round, position := picks/len(leaders), picks%len(leaders)
 
-	return nil, nil
+	return &leaders[l.DraftOrder.pickIndex(round, position, len(leaders))], nil
 }
 
 func (l *Lobby) DefinePriorities() {
@@ -268,44 +402,49 @@ func (l *Lobby) DefinePriorities() {
 		return l.Players[i].JoinTimestamp < l.Players[j].JoinTimestamp
 	})
 
-	currentPriority := 0
-	for i, p := range l.Players {
-		priority := calculatePriorityWeight(p, currentPriority)
-
+	for i, priority := range l.priorityStrategy().Rank(l.Players) {
 		if priority < 0 {
 			continue
 		}
 
 		l.Players[i].SelectionPriority = priority
-		currentPriority++
 	}
+
+	payload := PrioritiesDefinedPayload{Priorities: make(map[string]int, len(l.Players))}
+	for _, p := range l.Players {
+		payload.Priorities[p.ID] = p.SelectionPriority
+	}
+
+	l.emit(PrioritiesDefined, payload)
 }
 
 func (l *Lobby) SelectTeam(p profile.Profile, teamID int) error {
-	if l.Status != LeaderTeamSelect {
-		return errors.New("lobby is not in LeaderTeamSelect status")
+	if err := l.Can(SelectTeamAction, p); err != nil {
+		return err
 	}
 
 	leader := l.getPlayer(p.ID)
 
 	if leader == nil {
-		return errors.New("profile is not in the lobby")
+		return ErrProfileNotInLobby
 	}
 
 	if leader.Role != profile.Leader {
-		return errors.New("profile is not a leader")
-	}
-
-	if l.ChooseControl.ChoosingNow.ID != p.ID {
-		return errors.New("it is not the turn of the leader to choose")
+		return ErrNotLeader
 	}
 
 	if teamID < 0 || teamID >= len(l.Teams) {
-		return errors.New("teamID is invalid")
+		return ErrInvalidTeam
 	}
 
 	team := l.Teams[teamID]
+
+	if team.Leader.ID != "" {
+		return ErrTeamAlreadyTaken
+	}
 	team.Leader = *leader
+	at := l.emit(TeamSelected, TeamSelectedPayload{TeamID: team.ID, Leader: ResponseFromProfile(leader)})
+	l.recordDraftAction(DraftAction{Type: SelectTeam, TeamID: team.ID, Leader: *leader, Timestamp: at})
 
 	nextToSelect, err := l.GetNextLeader()
 	if err != nil {
@@ -314,7 +453,7 @@ func (l *Lobby) SelectTeam(p profile.Profile, teamID int) error {
 
 	l.removePlayer(p.ID)
 	if nextToSelect == nil || teamID == len(l.Teams)-1 {
-		l.Status = PlayerSelect
+		l.setStatus(PlayerSelect)
 		l.ChooseControl = nil // reset control
 
 		firstLeaderToChoose, err := l.GetNextTeamLeaderToPick()
@@ -323,7 +462,7 @@ func (l *Lobby) SelectTeam(p profile.Profile, teamID int) error {
 		}
 
 		if firstLeaderToChoose == nil {
-			return errors.New("error_getting_next_team_leader")
+			return ErrNextLeaderNotFound
 		}
 
 		chooseControl, err := NewSelectPlayerChooseControl(*firstLeaderToChoose)
@@ -331,60 +470,49 @@ func (l *Lobby) SelectTeam(p profile.Profile, teamID int) error {
 			return err
 		}
 
-		l.ChooseControl = chooseControl
+		l.setChooseControl(chooseControl)
 		return nil
 	}
 
-	l.ChooseControl.ChoosingNow = *nextToSelect
+	l.changeTurn(*nextToSelect)
 
 	return nil
 }
 
 func (l *Lobby) SelectPlayer(p profile.Profile, playerID string) error {
-	if l.Status != PlayerSelect {
-		return errors.New("lobby is not in PlayerSelect status")
-	}
-
-	if l.ChooseControl.ChoosingNow.ID != p.ID {
-		return errors.New("it is not the turn of the leader to choose")
+	if err := l.Can(SelectPlayerAction, p); err != nil {
+		return err
 	}
 
 	player := l.getPlayer(playerID)
 
 	if player == nil {
-		return errors.New("playerID is invalid")
+		return ErrInvalidPlayer
 	}
 
 	team := l.getTeamByLeaderID(p.ID)
 	if team == nil {
-		return errors.New("team not found")
+		return ErrTeamNotFound
 	}
 
 	team.Players = append(team.Players, *player)
+	at := l.emit(PlayerSelected, PlayerSelectedPayload{TeamID: team.ID, LeaderID: team.Leader.ID, Player: ResponseFromProfile(player)})
+	l.recordDraftAction(DraftAction{Type: SelectPlayer, TeamID: team.ID, Leader: team.Leader, Player: *player, Timestamp: at})
 	l.removePlayer(playerID)
 
-	nextToSelect, err := l.GetNextTeamLeaderToPick()
-	if err != nil {
-		return err
-	}
-
-	if nextToSelect == nil && len(l.Players) == 0 {
+	if len(l.Players) == 0 {
 		l.ChooseControl = nil
-		l.Status = ReadyToStart
+		l.setStatus(ReadyToStart)
 		return nil
 	}
 
-	if nextToSelect == nil {
-		l.ChooseControl = nil //reset control
-		nextToSelect, err = l.GetNextTeamLeaderToPick()
-	}
-
+	nextToSelect, err := l.GetNextTeamLeaderToPick()
 	if err != nil {
 		return err
 	}
 
 	if nextToSelect == nil {
-		return errors.New("error_getting_next_team_leader")
+		return ErrNextLeaderNotFound
 	}
 
 	chooseControl, err := NewSelectPlayerChooseControl(*nextToSelect)
@@ -392,26 +520,85 @@ func (l *Lobby) SelectPlayer(p profile.Profile, playerID string) error {
 		return err
 	}
 
-	l.ChooseControl = chooseControl
+	l.setChooseControl(chooseControl)
 
 	return nil
 }
 
-// less than 0 means that the player is not eligible to select
-// less is more eligible
-func calculatePriorityWeight(p profile.Profile, currentPriority int) int {
-	switch {
-	case p.HasSoftSkill(profile.Leadership) && p.HasHardSkill(profile.GDP):
-		return currentPriority
-	case p.HasSoftSkill(profile.Leadership):
-		return currentPriority + LeadershipWeight
-	case p.HasHardSkill(profile.GDP):
-		return currentPriority + GDPWeight
-	case p.Role == profile.Leader:
-		return currentPriority + ElectedLeaderWeight
-	}
-
-	return -1
+// AutoPick makes the choice of the leader whose turn expired: the
+// lowest-numbered free team, or the remaining player that adds the most
+// skills the team does not have yet.
+func (l *Lobby) AutoPick(now time.Time) error {
+	if l.ChooseControl == nil || l.ChooseControl.Deadline == 0 || now.Unix() < l.ChooseControl.Deadline {
+		return ErrTurnNotExpired
+	}
+
+	if _, err := l.transition(AutoPickAction); err != nil {
+		return err
+	}
+
+	leader := l.ChooseControl.ChoosingNow
+	l.emit(TurnExpired, TurnChangedPayload{
+		Type:        l.ChooseControl.Type,
+		ChoosingNow: ResponseFromProfile(&leader),
+		Deadline:    l.ChooseControl.Deadline,
+	})
+
+	switch l.Status {
+	case LeaderTeamSelect:
+		for _, team := range l.Teams {
+			if team.Leader.ID == "" {
+				return l.SelectTeam(leader, team.ID)
+			}
+		}
+
+		return ErrInvalidTeam
+	case PlayerSelect:
+		team := l.getTeamByLeaderID(leader.ID)
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		player := l.bestFitPlayer(team)
+		if player == nil {
+			return ErrInvalidPlayer
+		}
+
+		return l.SelectPlayer(leader, player.ID)
+	}
+
+	return invalidStatus(l.Status)
+}
+
+// bestFitPlayer returns the remaining player covering the most skills the
+// team is missing, the earliest to join winning ties.
+func (l *Lobby) bestFitPlayer(team *Team) *profile.Profile {
+	covered := team.coveredSkills()
+
+	var best *profile.Profile
+	bestScore := -1
+
+	for i, p := range l.Players {
+		score := 0
+		for _, skill := range skillKeys(p) {
+			if !covered[skill] {
+				score++
+			}
+		}
+
+		if score > bestScore || (score == bestScore && p.JoinTimestamp < best.JoinTimestamp) {
+			best = &l.Players[i]
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+func sortByPriority(profiles []profile.Profile) {
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].SelectionPriority < profiles[j].SelectionPriority
+	})
 }
 
 func (l *Lobby) hasSufficienteLeaders() bool {
@@ -438,6 +625,16 @@ func (l *Lobby) getPlayer(id string) *profile.Profile {
 	return nil
 }
 
+func (l *Lobby) getMentor(id string) *profile.Profile {
+	for i, p := range l.Mentors {
+		if p.ID == id {
+			return &l.Mentors[i]
+		}
+	}
+
+	return nil
+}
+
 func (l *Lobby) getTeamByLeaderID(leaderID string) *Team {
 	for _, t := range l.Teams {
 		if t.Leader.ID == leaderID {
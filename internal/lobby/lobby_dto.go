@@ -3,13 +3,41 @@ package lobby
 import "github.com/paq-devs/paq-be-rpg/internal/profile"
 
 type ProfileResponse struct {
-	ID            string              `json:"id"`
-	Avatar        string              `json:"avatar"`
-	Name          string              `json:"name"`
-	Role          profile.Role        `json:"role"`
-	HardSkills    []profile.HardSkill `json:"hard_skills"`
-	SoftSkills    []profile.SoftSkill `json:"soft_skills"`
-	JoinTimestamp int64               `json:"join_timestamp"`
+	ID                string                                    `json:"id"`
+	Avatar            string                                    `json:"avatar"`
+	Name              string                                    `json:"name"`
+	Role              profile.Role                              `json:"role"`
+	HardSkills        []profile.HardSkill                       `json:"hard_skills"`
+	SoftSkills        []profile.SoftSkill                       `json:"soft_skills"`
+	HardSkillLevels   map[profile.HardSkill]ProficiencyResponse `json:"hard_skill_levels"`
+	SoftSkillLevels   map[profile.SoftSkill]ProficiencyResponse `json:"soft_skill_levels"`
+	JoinTimestamp     int64                                     `json:"join_timestamp"`
+	SelectionPriority int                                       `json:"selection_priority"`
+}
+
+// IdentityResponse is a profile as participants keep it across lobbies.
+type IdentityResponse struct {
+	ID              string                                    `json:"id"`
+	Name            string                                    `json:"name"`
+	Avatar          string                                    `json:"avatar"`
+	HardSkills      []profile.HardSkill                       `json:"hard_skills"`
+	SoftSkills      []profile.SoftSkill                       `json:"soft_skills"`
+	HardSkillLevels map[profile.HardSkill]ProficiencyResponse `json:"hard_skill_levels"`
+	SoftSkillLevels map[profile.SoftSkill]ProficiencyResponse `json:"soft_skill_levels"`
+	CreatedAt       int64                                     `json:"created_at"`
+	UpdatedAt       int64                                     `json:"updated_at"`
+}
+
+// CreatedIdentityResponse is returned once, when the identity is created.
+// Only a hash of the secret is stored, so it can not be read again.
+type CreatedIdentityResponse struct {
+	*IdentityResponse
+	Secret string `json:"secret"`
+}
+
+type ProficiencyResponse struct {
+	Level profile.SkillLevel `json:"level"`
+	Notes string             `json:"notes,omitempty"`
 }
 
 type TeamResponse struct {
@@ -20,28 +48,136 @@ type TeamResponse struct {
 }
 
 type LobbyResponse struct {
-	AccessCode    string            `json:"access_code"`
-	Name          string            `json:"name"`
-	MaxHardSkills int               `json:"max_hard_skills"`
-	MaxSoftSkills int               `json:"max_soft_skills"`
-	Status        LobbyStatus       `json:"status"`
-	Players       []ProfileResponse `json:"players"`
-	Mentors       []ProfileResponse `json:"mentors"`
-	Master        ProfileResponse   `json:"master"`
-	Teams         []TeamResponse    `json:"teams"`
-	ChooseControl *ChooseControl    `json:"choose_control"`
+	AccessCode        string                `json:"access_code"`
+	Name              string                `json:"name"`
+	MaxHardSkills     int                   `json:"max_hard_skills"`
+	MaxSoftSkills     int                   `json:"max_soft_skills"`
+	MaxPlayers        int                   `json:"max_players"`
+	MaxMentors        int                   `json:"max_mentors"`
+	MinPlayersPerTeam int                   `json:"min_players_per_team"`
+	MaxPlayersPerTeam int                   `json:"max_players_per_team"`
+	TurnTimeout       int64                 `json:"turn_timeout_seconds"`
+	DraftOrder        DraftOrder            `json:"draft_order"`
+	TeamFormation     TeamFormation         `json:"team_formation"`
+	PriorityStrategy  PrioritySettings      `json:"priority_strategy"`
+	LeaderRule        LeaderRule            `json:"leader_rule"`
+	SkillCatalogue    SkillCatalogue        `json:"skill_catalogue"`
+	Status            LobbyStatus           `json:"status"`
+	StatusTimestamps  map[LobbyStatus]int64 `json:"status_timestamps"`
+	Players           []ProfileResponse     `json:"players"`
+	Mentors           []ProfileResponse     `json:"mentors"`
+	Waitlist          []ProfileResponse     `json:"waitlist"`
+	Master            ProfileResponse       `json:"master"`
+	Teams             []TeamResponse        `json:"teams"`
+	ChooseControl     *ChooseControl        `json:"choose_control"`
+	CreatedAt         int64                 `json:"created_at"`
+}
+
+// LobbySummaryResponse is the part of a lobby listings show, with its
+// members counted instead of listed.
+type LobbySummaryResponse struct {
+	AccessCode    string                 `json:"access_code,omitempty"` // only shown to the master of the lobby
+	Name          string                 `json:"name"`
+	Status        LobbyStatus            `json:"status"`
+	Master        ProfileSummaryResponse `json:"master"`
+	MaxPlayers    int                    `json:"max_players"`
+	MaxMentors    int                    `json:"max_mentors"`
+	PlayerCount   int                    `json:"player_count"`
+	MentorCount   int                    `json:"mentor_count"`
+	TeamCount     int                    `json:"team_count"`
+	WaitlistCount int                    `json:"waitlist_count"`
+	CreatedAt     int64                  `json:"created_at"`
+}
+
+type ProfileSummaryResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+type LobbySummaryPageResponse struct {
+	Lobbies    []LobbySummaryResponse `json:"lobbies"`
+	NextCursor string                 `json:"next_cursor,omitempty"` // absent on the last page
+}
+
+// HideAccessCodes leaves out the access code of the lobbies run by someone
+// other than the master, since the code is all it takes to join a lobby.
+func (page *LobbySummaryPageResponse) HideAccessCodes(masterID string) {
+	for i := range page.Lobbies {
+		if page.Lobbies[i].Master.ID != masterID {
+			page.Lobbies[i].AccessCode = ""
+		}
+	}
 }
 
 func ResponseFromProfile(p *profile.Profile) ProfileResponse {
 	return ProfileResponse{
-		ID:            p.ID,
-		Avatar:        p.Avatar,
-		Name:          p.Name,
-		Role:          p.Role,
-		HardSkills:    p.HardSkills,
-		SoftSkills:    p.SoftSkills,
-		JoinTimestamp: p.JoinTimestamp,
+		ID:                p.ID,
+		Avatar:            p.Avatar,
+		Name:              p.Name,
+		Role:              p.Role,
+		HardSkills:        p.HardSkills,
+		SoftSkills:        p.SoftSkills,
+		HardSkillLevels:   proficiencyResponses(p.HardSkillLevels),
+		SoftSkillLevels:   proficiencyResponses(p.SoftSkillLevels),
+		JoinTimestamp:     p.JoinTimestamp,
+		SelectionPriority: p.SelectionPriority,
+	}
+}
+
+func ResponseFromIdentity(i *profile.Identity) *IdentityResponse {
+	return &IdentityResponse{
+		ID:              i.ID,
+		Name:            i.Name,
+		Avatar:          i.Avatar,
+		HardSkills:      i.HardSkills,
+		SoftSkills:      i.SoftSkills,
+		HardSkillLevels: proficiencyResponses(i.HardSkillLevels),
+		SoftSkillLevels: proficiencyResponses(i.SoftSkillLevels),
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
+	}
+}
+
+func (p ProfileResponse) toProfile() profile.Profile {
+	return profile.Profile{
+		ID:                p.ID,
+		Name:              p.Name,
+		Avatar:            p.Avatar,
+		HardSkills:        p.HardSkills,
+		SoftSkills:        p.SoftSkills,
+		HardSkillLevels:   proficiencies(p.HardSkillLevels),
+		SoftSkillLevels:   proficiencies(p.SoftSkillLevels),
+		Role:              p.Role,
+		JoinTimestamp:     p.JoinTimestamp,
+		SelectionPriority: p.SelectionPriority,
+	}
+}
+
+func proficiencyResponses[S profile.HardSkill | profile.SoftSkill](levels map[S]profile.Proficiency) map[S]ProficiencyResponse {
+	if levels == nil {
+		return nil
+	}
+
+	responses := make(map[S]ProficiencyResponse, len(levels))
+	for skill, proficiency := range levels {
+		responses[skill] = ProficiencyResponse(proficiency)
 	}
+
+	return responses
+}
+
+func proficiencies[S profile.HardSkill | profile.SoftSkill](responses map[S]ProficiencyResponse) map[S]profile.Proficiency {
+	if responses == nil {
+		return nil
+	}
+
+	levels := make(map[S]profile.Proficiency, len(responses))
+	for skill, response := range responses {
+		levels[skill] = profile.Proficiency(response)
+	}
+
+	return levels
 }
 
 func ResponseFromTeam(team *Team) TeamResponse {
@@ -63,6 +199,7 @@ func ResponseFromTeam(team *Team) TeamResponse {
 func ResponseFromLobby(lobby *Lobby) *LobbyResponse {
 	players := make([]ProfileResponse, 0)
 	mentors := make([]ProfileResponse, 0)
+	waitlist := make([]ProfileResponse, 0)
 	teams := make([]TeamResponse, 0)
 
 	for _, player := range lobby.Players {
@@ -73,20 +210,70 @@ func ResponseFromLobby(lobby *Lobby) *LobbyResponse {
 		mentors = append(mentors, ResponseFromProfile(&mentor))
 	}
 
+	for _, p := range lobby.Waitlist {
+		waitlist = append(waitlist, ResponseFromProfile(&p))
+	}
+
 	for _, team := range lobby.Teams {
 		teams = append(teams, ResponseFromTeam(team))
 	}
 
 	return &LobbyResponse{
-		AccessCode:    lobby.AccessCode,
-		Name:          lobby.Name,
-		MaxHardSkills: lobby.MaxHardSkills,
-		MaxSoftSkills: lobby.MaxSoftSkills,
-		Status:        lobby.Status,
-		Players:       players,
-		Mentors:       mentors,
-		Master:        ResponseFromProfile(&lobby.Master),
-		Teams:         teams,
-		ChooseControl: lobby.ChooseControl,
+		AccessCode:        lobby.AccessCode,
+		Name:              lobby.Name,
+		MaxHardSkills:     lobby.MaxHardSkills,
+		MaxSoftSkills:     lobby.MaxSoftSkills,
+		MaxPlayers:        lobby.MaxPlayers,
+		MaxMentors:        lobby.MaxMentors,
+		MinPlayersPerTeam: lobby.MinPlayersPerTeam,
+		MaxPlayersPerTeam: lobby.MaxPlayersPerTeam,
+		TurnTimeout:       int64(lobby.TurnTimeout.Seconds()),
+		DraftOrder:        lobby.DraftOrder,
+		TeamFormation:     lobby.TeamFormation,
+		PriorityStrategy:  lobby.priorityStrategy().Settings(),
+		LeaderRule:        lobby.leaderRule(),
+		SkillCatalogue:    lobby.skillCatalogue(),
+		Status:            lobby.Status,
+		StatusTimestamps:  lobby.StatusTimestamps,
+		Players:           players,
+		Mentors:           mentors,
+		Waitlist:          waitlist,
+		Master:            ResponseFromProfile(&lobby.Master),
+		Teams:             teams,
+		ChooseControl:     lobby.ChooseControl,
+		CreatedAt:         lobby.CreatedAt,
+	}
+}
+
+func ResponseFromLobbySummary(lobby *Lobby) LobbySummaryResponse {
+	return LobbySummaryResponse{
+		AccessCode: lobby.AccessCode,
+		Name:       lobby.Name,
+		Status:     lobby.Status,
+		Master: ProfileSummaryResponse{
+			ID:     lobby.Master.ID,
+			Name:   lobby.Master.Name,
+			Avatar: lobby.Master.Avatar,
+		},
+		MaxPlayers:    lobby.MaxPlayers,
+		MaxMentors:    lobby.MaxMentors,
+		PlayerCount:   len(lobby.Players),
+		MentorCount:   len(lobby.Mentors),
+		TeamCount:     len(lobby.Teams),
+		WaitlistCount: len(lobby.Waitlist),
+		CreatedAt:     lobby.CreatedAt,
+	}
+}
+
+func ResponseFromLobbyPage(page LobbyPage) *LobbySummaryPageResponse {
+	lobbies := make([]LobbySummaryResponse, 0)
+
+	for _, lobby := range page.Lobbies {
+		lobbies = append(lobbies, ResponseFromLobbySummary(lobby))
+	}
+
+	return &LobbySummaryPageResponse{
+		Lobbies:    lobbies,
+		NextCursor: page.NextCursor,
 	}
 }
@@ -0,0 +1,163 @@
+package lobby
+
+import (
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+type LobbyEventType string
+
+const (
+	LobbyCreated      LobbyEventType = "lobby_created"
+	PlayerJoined      LobbyEventType = "player_joined"
+	MentorJoined      LobbyEventType = "mentor_joined"
+	ProfileLeft       LobbyEventType = "profile_left"
+	ProfileKicked     LobbyEventType = "profile_kicked"
+	ProfileRejoined   LobbyEventType = "profile_rejoined"
+	ProfileWaitlisted LobbyEventType = "profile_waitlisted"
+	WaitlistPromoted  LobbyEventType = "waitlist_promoted"
+	WaitlistDismissed LobbyEventType = "waitlist_dismissed" // the lobby closed before there was room for the profile
+	StatusChanged     LobbyEventType = "status_changed"
+	LeaderPromoted    LobbyEventType = "leader_promoted"
+	TeamsBuilt        LobbyEventType = "teams_built"
+	PrioritiesDefined LobbyEventType = "priorities_defined"
+	TeamSelected      LobbyEventType = "team_selected"
+	PlayerSelected    LobbyEventType = "player_selected"
+	TurnChanged       LobbyEventType = "turn_changed"
+	SelectionUndone   LobbyEventType = "selection_undone"
+	TurnExpired       LobbyEventType = "turn_expired"   // the next selection was made automatically
+	TeamsBalanced     LobbyEventType = "teams_balanced" // payload has the seed and the coverage of every team
+	LobbyUpdated      LobbyEventType = "lobby_updated"  // payload is the LobbyResponse after the change
+)
+
+// SystemActor is the actor of the events caused by the server itself, such
+// as the creation of the teams or an expired turn.
+const SystemActor = "system"
+
+// LobbyEvent describes a change applied to a lobby. Sequence is assigned by
+// the EventBroker when the event is published and grows per lobby. Actor is
+// the ID of the profile that caused the change, Version the version of the
+// lobby the change produced and Index the position of the event among the
+// ones of that version.
+type LobbyEvent struct {
+	Sequence   uint64         `json:"sequence"`
+	Type       LobbyEventType `json:"type"`
+	AccessCode string         `json:"access_code"`
+	Actor      string         `json:"actor,omitempty"`
+	Version    int            `json:"version"`
+	Index      int            `json:"index"`
+	Timestamp  int64          `json:"timestamp"`
+	Payload    interface{}    `json:"payload"`
+}
+
+type LobbyCreatedPayload struct {
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	Master            ProfileResponse  `json:"master"`
+	MaxHardSkills     int              `json:"max_hard_skills"`
+	MaxSoftSkills     int              `json:"max_soft_skills"`
+	MaxPlayers        int              `json:"max_players"`
+	MaxMentors        int              `json:"max_mentors"`
+	MinPlayersPerTeam int              `json:"min_players_per_team"`
+	MaxPlayersPerTeam int              `json:"max_players_per_team"`
+	TurnTimeout       time.Duration    `json:"turn_timeout"`
+	DraftOrder        DraftOrder       `json:"draft_order"`
+	TeamFormation     TeamFormation    `json:"team_formation"`
+	PriorityStrategy  PrioritySettings `json:"priority_strategy"`
+	LeaderRule        LeaderRule       `json:"leader_rule"`
+	SkillCatalogue    SkillCatalogue   `json:"skill_catalogue"`
+}
+
+type TeamsBuiltPayload struct {
+	Teams []TeamResponse `json:"teams"`
+}
+
+// PrioritiesDefinedPayload has the selection priority of every player, by
+// profile ID.
+type PrioritiesDefinedPayload struct {
+	Priorities map[string]int `json:"priorities"`
+}
+
+type StatusChangedPayload struct {
+	From LobbyStatus `json:"from"`
+	To   LobbyStatus `json:"to"`
+}
+
+type TeamSelectedPayload struct {
+	TeamID int             `json:"team_id"`
+	Leader ProfileResponse `json:"leader"`
+}
+
+type PlayerSelectedPayload struct {
+	TeamID   int             `json:"team_id"`
+	LeaderID string          `json:"leader_id"`
+	Player   ProfileResponse `json:"player"`
+}
+
+type TurnChangedPayload struct {
+	Type        ChooseType      `json:"type"`
+	ChoosingNow ProfileResponse `json:"choosing_now"`
+	Deadline    int64           `json:"deadline,omitempty"`
+}
+
+// PullEvents returns the events recorded since the last call and clears them.
+func (l *Lobby) PullEvents() []LobbyEvent {
+	events := l.events
+	l.events = nil
+	return events
+}
+
+// emit records an event and returns its timestamp, so the state changed
+// along with it can carry the same time.
+func (l *Lobby) emit(eventType LobbyEventType, payload interface{}) int64 {
+	event := LobbyEvent{
+		Type:       eventType,
+		AccessCode: l.AccessCode,
+		Timestamp:  time.Now().Unix(),
+		Payload:    payload,
+	}
+
+	l.events = append(l.events, event)
+	return event.Timestamp
+}
+
+func (l *Lobby) setStatus(status LobbyStatus) {
+	if l.Status == status {
+		return
+	}
+
+	at := l.emit(StatusChanged, StatusChangedPayload{From: l.Status, To: status})
+	l.Status = status
+
+	if l.StatusTimestamps == nil {
+		l.StatusTimestamps = make(map[LobbyStatus]int64)
+	}
+	l.StatusTimestamps[status] = at
+}
+
+// setChooseControl starts a new turn, with a deadline when the lobby has a
+// turn timeout and a leader is choosing.
+func (l *Lobby) setChooseControl(chooseControl *ChooseControl) {
+	l.ChooseControl = chooseControl
+
+	if chooseControl == nil {
+		return
+	}
+
+	chooseControl.Deadline = 0
+	if l.TurnTimeout > 0 && chooseControl.Type != PromoteLeader {
+		chooseControl.Deadline = time.Now().Add(l.TurnTimeout).Unix()
+	}
+
+	l.emit(TurnChanged, TurnChangedPayload{
+		Type:        chooseControl.Type,
+		ChoosingNow: ResponseFromProfile(&chooseControl.ChoosingNow),
+		Deadline:    chooseControl.Deadline,
+	})
+}
+
+func (l *Lobby) changeTurn(p profile.Profile) {
+	l.ChooseControl.ChoosingNow = p
+	l.setChooseControl(l.ChooseControl)
+}
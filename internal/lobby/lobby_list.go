@@ -0,0 +1,176 @@
+package lobby
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// listedStatuses are every status a lobby can be in, and so be listed by.
+var listedStatuses = map[LobbyStatus]bool{
+	Waiting:          true,
+	CreatingTeam:     true,
+	TeamsCreated:     true,
+	LeaderElection:   true,
+	LeaderTeamSelect: true,
+	PlayerSelect:     true,
+	ReadyToStart:     true,
+	InProgress:       true,
+	Paused:           true,
+	Finished:         true,
+	Archived:         true,
+}
+
+// Known reports whether a lobby can be in the status.
+func (s LobbyStatus) Known() bool {
+	return listedStatuses[s]
+}
+
+// LobbyFilter narrows the lobbies listed. Fields left empty match every
+// lobby.
+type LobbyFilter struct {
+	Status      LobbyStatus
+	MasterID    string
+	Name        string // found anywhere in the name, ignoring case
+	CreatedFrom int64  // unix timestamp, inclusive
+	CreatedTo   int64  // unix timestamp, exclusive
+}
+
+func (f LobbyFilter) Matches(l *Lobby) bool {
+	switch {
+	case f.Status != "" && l.Status != f.Status:
+		return false
+	case f.MasterID != "" && l.Master.ID != f.MasterID:
+		return false
+	case f.Name != "" && !strings.Contains(strings.ToLower(l.Name), strings.ToLower(f.Name)):
+		return false
+	case f.CreatedFrom != 0 && l.CreatedAt < f.CreatedFrom:
+		return false
+	case f.CreatedTo != 0 && l.CreatedAt >= f.CreatedTo:
+		return false
+	}
+
+	return true
+}
+
+// PageRequest asks for the lobbies after Cursor, the NextCursor of the
+// previous page, or for the first page when it is empty. Lobbies are listed
+// newest first.
+type PageRequest struct {
+	Cursor string
+	Limit  int // DefaultPageLimit when 0
+}
+
+// Size is the number of lobbies in a full page.
+func (p PageRequest) Size() int {
+	if p.Limit == 0 {
+		return DefaultPageLimit
+	}
+
+	return p.Limit
+}
+
+func (p PageRequest) Validate() error {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return ErrInvalidPage.WithDetails(map[string]interface{}{"limit": p.Limit, "max": MaxPageLimit})
+	}
+
+	_, err := p.After()
+	return err
+}
+
+// After returns the last lobby of the previous page, nil for the first page.
+func (p PageRequest) After() (*LobbyCursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+
+	invalid := ErrInvalidPage.WithDetails(map[string]interface{}{"cursor": p.Cursor})
+
+	decoded, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	createdAt, id, ok := strings.Cut(string(decoded), ":")
+	if !ok || id == "" {
+		return nil, invalid
+	}
+
+	at, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+
+	return &LobbyCursor{CreatedAt: at, ID: id}, nil
+}
+
+// LobbyCursor is the position of a lobby in the listing, which is sorted by
+// CreatedAt and then ID, both descending.
+type LobbyCursor struct {
+	CreatedAt int64
+	ID        string
+}
+
+func CursorOf(l *Lobby) LobbyCursor {
+	return LobbyCursor{CreatedAt: l.CreatedAt, ID: l.ID}
+}
+
+func (c LobbyCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt, 10) + ":" + c.ID))
+}
+
+// Precedes reports whether l is listed after the cursor.
+func (c LobbyCursor) Precedes(l *Lobby) bool {
+	return l.CreatedAt < c.CreatedAt || (l.CreatedAt == c.CreatedAt && l.ID < c.ID)
+}
+
+// LobbyPage holds at most PageRequest.Size lobbies.
+type LobbyPage struct {
+	Lobbies    []*Lobby
+	NextCursor string // empty on the last page
+}
+
+// NewLobbyPage builds the page from the lobbies a repository read past the
+// cursor, in order, asking for one more than Size to know whether another
+// page follows.
+func NewLobbyPage(lobbies []*Lobby, page PageRequest) LobbyPage {
+	if len(lobbies) <= page.Size() {
+		return LobbyPage{Lobbies: lobbies}
+	}
+
+	lobbies = lobbies[:page.Size()]
+	return LobbyPage{Lobbies: lobbies, NextCursor: CursorOf(lobbies[len(lobbies)-1]).String()}
+}
+
+// PageOf lists the lobbies held in memory as the repositories backed by a
+// database do.
+func PageOf(lobbies []*Lobby, filter LobbyFilter, page PageRequest) (LobbyPage, error) {
+	after, err := page.After()
+	if err != nil {
+		return LobbyPage{}, err
+	}
+
+	listed := make([]*Lobby, 0)
+	for _, l := range lobbies {
+		if filter.Matches(l) && (after == nil || after.Precedes(l)) {
+			listed = append(listed, l)
+		}
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		return CursorOf(listed[i]).Precedes(listed[j])
+	})
+
+	if len(listed) > page.Size()+1 {
+		listed = listed[:page.Size()+1]
+	}
+
+	return NewLobbyPage(listed, page), nil
+}
@@ -0,0 +1,156 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestPageRequest_Validate(t *testing.T) {
+	cursor := LobbyCursor{CreatedAt: 1000, ID: "lobby"}.String()
+
+	cases := []struct {
+		name  string
+		page  PageRequest
+		valid bool
+	}{
+		{"first page", PageRequest{}, true},
+		{"largest page", PageRequest{Limit: MaxPageLimit}, true},
+		{"cursor", PageRequest{Cursor: cursor, Limit: 1}, true},
+		{"negative limit", PageRequest{Limit: -1}, false},
+		{"limit too large", PageRequest{Limit: MaxPageLimit + 1}, false},
+		{"cursor not encoded", PageRequest{Cursor: "not a cursor"}, false},
+		{"cursor without id", PageRequest{Cursor: LobbyCursor{CreatedAt: 1000}.String()}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.page.Validate()
+
+			if c.valid && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+
+			if !c.valid && !errors.Is(err, ErrInvalidPage) {
+				t.Errorf("Expected ErrInvalidPage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPageRequest_After(t *testing.T) {
+	cursor := LobbyCursor{CreatedAt: 1000, ID: "a:b"}
+
+	after, err := PageRequest{Cursor: cursor.String()}.After()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if after == nil || *after != cursor {
+		t.Errorf("Expected cursor %+v, got %+v", cursor, after)
+	}
+
+	if after, _ := (PageRequest{}).After(); after != nil {
+		t.Errorf("Expected no cursor on the first page, got %+v", after)
+	}
+}
+
+func TestPageRequest_Size(t *testing.T) {
+	if size := (PageRequest{}).Size(); size != DefaultPageLimit {
+		t.Errorf("Expected Size to be %d, got %d", DefaultPageLimit, size)
+	}
+
+	if size := (PageRequest{Limit: 5}).Size(); size != 5 {
+		t.Errorf("Expected Size to be 5, got %d", size)
+	}
+}
+
+func TestListLobbiesService(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.NewMaster("Master", "avatar")
+	for i, createdAt := range []int64{1000, 2000, 3000} {
+		lobby := NewLobby(master, "Lobby", 1, 1, WithCapacity(Capacity{MaxPlayers: 1}))
+		lobby.CreatedAt = createdAt
+
+		if i == 2 {
+			_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+			_ = lobby.Join(profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership}))
+			_ = lobby.Join(profile.NewPlayer("Waitlisted", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership}))
+		}
+
+		_ = repo.Save(context.Background(), lobby)
+	}
+
+	first, err := service.ListLobbies(context.Background(), LobbyFilter{MasterID: master.ID}, PageRequest{Limit: 2})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(first.Lobbies) != 2 || first.NextCursor == "" {
+		t.Fatalf("Expected 2 lobbies and a next cursor, got %d lobbies and %q", len(first.Lobbies), first.NextCursor)
+	}
+
+	newest := first.Lobbies[0]
+	if newest.CreatedAt != 3000 {
+		t.Errorf("Expected the newest lobby first, got one created at %d", newest.CreatedAt)
+	}
+
+	if newest.PlayerCount != 1 || newest.MentorCount != 1 || newest.WaitlistCount != 1 || newest.TeamCount != 0 {
+		t.Errorf("Expected 1 player, 1 mentor, 1 waitlisted and no team, got %+v", newest)
+	}
+
+	if newest.Master.ID != master.ID || newest.Master.Name != "Master" {
+		t.Errorf("Expected master %s, got %+v", master.ID, newest.Master)
+	}
+
+	last, err := service.ListLobbies(context.Background(), LobbyFilter{MasterID: master.ID}, PageRequest{Cursor: first.NextCursor, Limit: 2})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(last.Lobbies) != 1 || last.Lobbies[0].CreatedAt != 1000 || last.NextCursor != "" {
+		t.Errorf("Expected only the oldest lobby and no next cursor, got %+v", last)
+	}
+
+	if _, err := service.ListLobbies(context.Background(), LobbyFilter{}, PageRequest{Limit: MaxPageLimit + 1}); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("Expected ErrInvalidPage, got %v", err)
+	}
+}
+
+func TestLobbyStatus_Known(t *testing.T) {
+	for _, status := range []LobbyStatus{Waiting, PlayerSelect, ReadyToStart, InProgress, Archived} {
+		if !status.Known() {
+			t.Errorf("Expected %s to be known", status)
+		}
+	}
+
+	for _, status := range []LobbyStatus{"", "waiting", "Started"} {
+		if status.Known() {
+			t.Errorf("Expected %q to be unknown", status)
+		}
+	}
+}
+
+func TestLobbySummaryPageResponse_HideAccessCodes(t *testing.T) {
+	page := &LobbySummaryPageResponse{Lobbies: []LobbySummaryResponse{
+		{AccessCode: "MINE01", Master: ProfileSummaryResponse{ID: "master"}},
+		{AccessCode: "THEIRS", Master: ProfileSummaryResponse{ID: "other"}},
+	}}
+
+	page.HideAccessCodes("master")
+
+	if page.Lobbies[0].AccessCode != "MINE01" {
+		t.Errorf("Expected the access code of the own lobby to be kept, got %q", page.Lobbies[0].AccessCode)
+	}
+
+	if page.Lobbies[1].AccessCode != "" {
+		t.Errorf("Expected the access code of another master to be hidden, got %q", page.Lobbies[1].AccessCode)
+	}
+}
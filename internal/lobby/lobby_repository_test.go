@@ -0,0 +1,14 @@
+package lobby_test
+
+import (
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/lobby/lobbytest"
+)
+
+func TestLobbyRepositoryMock(t *testing.T) {
+	lobbytest.Run(t, func(t *testing.T) lobby.LobbyRepository {
+		return lobby.NewLobbyRepositoryMock()
+	})
+}
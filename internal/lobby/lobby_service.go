@@ -2,35 +2,98 @@ package lobby
 
 import (
 	"context"
+	"errors"
+	"log"
 	"time"
 
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
 	"github.com/patrickmn/go-cache"
 )
 
+// maxUpdateAttempts bounds how many times a domain operation is reloaded and
+// re-applied when the repository reports a version conflict.
+const maxUpdateAttempts = 3
+
+// deliveryAttempts bounds how many times the outbox of a lobby is appended to
+// the event log in a row. Events not appended stay in the outbox, stored with
+// the lobby, and are appended with the next write or read of the event log.
+const deliveryAttempts = 3
+
+// deliveryBackoff is how long the first retry of a delivery waits, each
+// retry waiting longer.
+var deliveryBackoff = 100 * time.Millisecond
+
+// turnRetryDelay is how long an expired turn waits before its automatic
+// choice is tried again, after the previous try failed.
+var turnRetryDelay = time.Second
+
 type LobbyRepository interface {
 	Save(ctx context.Context, lobby *Lobby) error
+	// FindByAccessCode returns ErrLobbyNotFound, and no lobby, when no lobby
+	// has the access code. Each lobby returned is a copy of the stored one.
 	FindByAccessCode(ctx context.Context, accessCode string) (*Lobby, error)
+	// Update persists the lobby only if the stored version still matches
+	// lobby.Version, returning a *VersionConflictError otherwise.
 	Update(ctx context.Context, lobby *Lobby) error
+	// FindWithTurnDeadline returns the lobbies whose current turn has a deadline.
+	FindWithTurnDeadline(ctx context.Context) ([]*Lobby, error)
+	// List returns the lobbies matching the filter newest first, a page at a
+	// time, and ErrInvalidPage for a cursor it did not return.
+	List(ctx context.Context, filter LobbyFilter, page PageRequest) (LobbyPage, error)
 }
 
 type LobbyService struct {
-	repo  LobbyRepository
-	cache *cache.Cache
+	repo        LobbyRepository
+	history     LobbyEventStore
+	cache       *cache.Cache
+	events      *EventBroker
+	turns       *turnScheduler
+	accessCodes AccessCodeGenerator
 }
 
-func NewLobbyService(repo LobbyRepository) *LobbyService {
+func NewLobbyService(repo LobbyRepository, history LobbyEventStore) *LobbyService {
 	c := cache.New(1*time.Minute, 10*time.Minute)
 	return &LobbyService{
-		repo:  repo,
-		cache: c,
+		repo:        repo,
+		history:     history,
+		cache:       c,
+		events:      NewEventBroker(defaultEventHistorySize, defaultMaxSubscribers, defaultStreamIdleTTL),
+		turns:       newTurnScheduler(),
+		accessCodes: DefaultAccessCodeGenerator(),
+	}
+}
+
+// SetAccessCodeGenerator changes how the access codes of the lobbies created
+// from now on are drawn.
+func (service *LobbyService) SetAccessCodeGenerator(generator AccessCodeGenerator) error {
+	if err := generator.Validate(); err != nil {
+		return err
 	}
+
+	service.accessCodes = generator
+	return nil
 }
 
-func (service *LobbyService) CreateLobby(ctx context.Context, master profile.Profile, name string, maxHardSkills int, maxSoftSkills int) (*LobbyResponse, error) {
-	lobby := NewLobby(master, name, maxHardSkills, maxSoftSkills)
+// CreateLobby draws a new access code, with a new lobby, each time the
+// repository reports the code is already taken.
+func (service *LobbyService) CreateLobby(ctx context.Context, master profile.Profile, name string, maxHardSkills int, maxSoftSkills int, opts ...LobbyOption) (*LobbyResponse, error) {
+	var lobby *Lobby
+
+	for attempt := 1; ; attempt++ {
+		lobby = NewLobby(master, name, maxHardSkills, maxSoftSkills, append([]LobbyOption{WithAccessCode(service.accessCodes.Generate())}, opts...)...)
+		service.stage(lobby, master.ID, lobby.Version)
+
+		err := service.repo.Save(ctx, lobby)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, ErrAccessCodeTaken) || attempt == maxAccessCodeAttempts {
+			return nil, err
+		}
+	}
 
-	service.repo.Save(ctx, lobby)
+	service.deliverOrLog(ctx, lobby)
 	return ResponseFromLobby(lobby), nil
 }
 
@@ -45,181 +108,532 @@ func (service *LobbyService) GetLobby(ctx context.Context, accessCode string) (*
 	}
 
 	if lobby == nil {
-		return nil, nil
+		return nil, ErrLobbyNotFound
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
+	return service.cacheLobby(lobby), nil
+}
 
-	return lobbyResponse, nil
+// ListLobbies summarises the lobbies matching the filter, newest first, a
+// page at a time.
+func (service *LobbyService) ListLobbies(ctx context.Context, filter LobbyFilter, page PageRequest) (*LobbySummaryPageResponse, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+
+	lobbies, err := service.repo.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResponseFromLobbyPage(lobbies), nil
 }
 
-func (service *LobbyService) JoinLobby(ctx context.Context, accessCode string, player profile.Profile) (*LobbyResponse, error) {
+// AvailableActions lists what the actor can do in the lobby right now.
+func (service *LobbyService) AvailableActions(ctx context.Context, accessCode string, actor profile.Profile) ([]AvailableAction, error) {
 	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
 	if err != nil {
 		return nil, err
 	}
 
 	if lobby == nil {
-		return nil, nil
+		return nil, ErrLobbyNotFound
 	}
 
-	lobby.Join(player)
-	err = service.repo.Update(ctx, lobby)
+	return lobby.AvailableActions(actor), nil
+}
+
+// Timeline returns the selections made during the draft of the lobby.
+func (service *LobbyService) Timeline(ctx context.Context, accessCode string) ([]TimelineEntry, error) {
+	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
 	if err != nil {
 		return nil, err
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
+	if lobby == nil {
+		return nil, ErrLobbyNotFound
+	}
 
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
-	return lobbyResponse, nil
+	return lobby.Timeline(), nil
 }
 
-func (service *LobbyService) StartTeamCreation(ctx context.Context, accessCode string) (*LobbyResponse, error) {
+// Events returns the event log of the lobby, oldest first.
+func (service *LobbyService) Events(ctx context.Context, accessCode string) ([]LobbyEvent, error) {
 	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
 	if err != nil {
 		return nil, err
 	}
 
 	if lobby == nil {
-		return nil, nil
+		return nil, ErrLobbyNotFound
+	}
+
+	if err := service.deliver(ctx, lobby); err != nil {
+		return nil, err
+	}
+
+	return service.history.Load(ctx, accessCode)
+}
+
+// VerifyEventLog replays the event log of the lobby and returns
+// ErrEventLogMismatch when the result differs from the stored lobby.
+func (service *LobbyService) VerifyEventLog(ctx context.Context, accessCode string) error {
+	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
+	if err != nil {
+		return err
+	}
+
+	if lobby == nil {
+		return ErrLobbyNotFound
+	}
+
+	if err := service.deliver(ctx, lobby); err != nil {
+		return err
+	}
+
+	events, err := service.history.Load(ctx, accessCode)
+	if err != nil {
+		return err
 	}
 
-	err = lobby.StartTeamCreation()
+	replayed, err := Replay(events)
+	if err != nil {
+		return err
+	}
+
+	if !replayed.SameState(lobby) {
+		return ErrEventLogMismatch.WithDetails(map[string]interface{}{"version": lobby.Version, "events": len(events)})
+	}
+
+	return nil
+}
+
+// SubscribeEvents streams the events published for the lobby, starting after
+// the since sequence number.
+func (service *LobbyService) SubscribeEvents(ctx context.Context, accessCode string, since uint64) (*Subscription, []LobbyEvent, error) {
+	_, err := service.GetLobby(ctx, accessCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.events.Subscribe(accessCode, since)
+}
+
+func (service *LobbyService) JoinLobby(ctx context.Context, accessCode string, player profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, player.ID, func(lobby *Lobby) error {
+		return lobby.Join(player)
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.repo.Update(ctx, lobby)
+	return service.cacheLobby(lobby), nil
+}
+
+func (service *LobbyService) LeaveLobby(ctx context.Context, accessCode string, p profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, p.ID, func(lobby *Lobby) error {
+		return lobby.Leave(p.ID)
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
+	return service.cacheLobby(lobby), nil
+}
 
-	go service.createTeams(lobby)
-	return lobbyResponse, nil
+func (service *LobbyService) KickFromLobby(ctx context.Context, accessCode string, master profile.Profile, profileID string) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		return lobby.Kick(master.ID, profileID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return service.cacheLobby(lobby), nil
 }
 
-func (service *LobbyService) createTeams(lobby *Lobby) {
-	err := lobby.CreateTeams()
+// RejoinLobby restores a profile that left the lobby, keeping its original
+// JoinTimestamp.
+func (service *LobbyService) RejoinLobby(ctx context.Context, accessCode string, p profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, p.ID, func(lobby *Lobby) error {
+		return lobby.Rejoin(p.ID)
+	})
 
 	if err != nil {
-		service.moveToWaiting(context.Background(), lobby) // rollback to waiting
-		return
+		return nil, err
 	}
 
-	if lobby.Status == TeamsCreated {
-		err = lobby.StartLeaderTeamSelection()
+	return service.cacheLobby(lobby), nil
+}
 
+func (service *LobbyService) StartTeamCreation(ctx context.Context, accessCode string, master profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		err := lobby.Can(CloseAction, master)
 		if err != nil {
-			service.moveToWaiting(context.Background(), lobby) // rollback to waiting
-			return
+			return err
 		}
+
+		return lobby.StartTeamCreation()
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	err = service.repo.Update(context.Background(), lobby)
+	lobbyResponse := service.cacheLobby(lobby)
+
+	go service.createTeams(accessCode)
+	return lobbyResponse, nil
+}
+
+func (service *LobbyService) createTeams(accessCode string) {
+	ctx := context.Background()
+
+	lobby, err := service.mutate(ctx, accessCode, SystemActor, func(lobby *Lobby) error {
+		err := lobby.CreateTeams()
+		if err != nil {
+			return err
+		}
+
+		if lobby.Status == TeamsCreated && lobby.TeamFormation != BalancedFormation {
+			return lobby.StartLeaderTeamSelection()
+		}
+
+		return nil
+	})
+
 	if err != nil {
+		service.moveToWaiting(ctx, accessCode) // rollback to waiting
 		return
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(lobby.AccessCode, lobbyResponse, cache.DefaultExpiration)
+	service.cacheLobby(lobby)
 }
 
-func (service *LobbyService) moveToWaiting(ctx context.Context, lobby *Lobby) {
-	lobby.Status = Waiting
+func (service *LobbyService) moveToWaiting(ctx context.Context, accessCode string) {
+	lobby, err := service.mutate(ctx, accessCode, SystemActor, func(lobby *Lobby) error {
+		if lobby.Status != CreatingTeam { // the teams were created in the meantime
+			return nil
+		}
+
+		return lobby.CancelTeamCreation()
+	})
 
-	err := service.repo.Update(ctx, lobby)
 	if err != nil {
 		return
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(lobby.AccessCode, lobbyResponse, cache.DefaultExpiration)
+	service.cacheLobby(lobby)
 }
 
-func (service *LobbyService) PromoteLeader(ctx context.Context, accessCode string, player profile.Profile) (*LobbyResponse, error) {
-	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
+func (service *LobbyService) PromoteLeader(ctx context.Context, accessCode string, master profile.Profile, player profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		err := lobby.Can(PromoteAction, master)
+		if err != nil {
+			return err
+		}
+
+		err = lobby.PromoteLeader(player)
+		if err != nil {
+			return err
+		}
+
+		if lobby.Status == TeamsCreated && lobby.TeamFormation != BalancedFormation {
+			return lobby.StartLeaderTeamSelection()
+		}
+
+		return nil
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	if lobby == nil {
-		return nil, nil
-	}
+	return service.cacheLobby(lobby), nil
+}
+
+// ChangeStatus applies a lifecycle action of the Master, such as starting or
+// finishing the session.
+func (service *LobbyService) ChangeStatus(ctx context.Context, accessCode string, master profile.Profile, action LobbyAction) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		err := lobby.Can(action, master)
+		if err != nil {
+			return err
+		}
+
+		return lobby.Apply(action)
+	})
 
-	err = lobby.PromoteLeader(player)
 	if err != nil {
 		return nil, err
 	}
 
-	if lobby.Status == TeamsCreated {
-		err = lobby.StartLeaderTeamSelection()
+	return service.cacheLobby(lobby), nil
+}
 
+// BalanceTeams fills the teams of a lobby waiting in TeamsCreated and returns
+// the skill coverage of every team.
+func (service *LobbyService) BalanceTeams(ctx context.Context, accessCode string, master profile.Profile, seed int64) (*LobbyResponse, []TeamCoverage, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		err := lobby.Can(BalanceTeamsAction, master)
 		if err != nil {
-			return nil, err
+			return err
 		}
+
+		return lobby.BalanceTeams(seed)
+	})
+
+	if err != nil {
+		return nil, nil, err
 	}
 
-	err = service.repo.Update(ctx, lobby)
+	return service.cacheLobby(lobby), lobby.TeamCoverage(), nil
+}
+
+// UndoSelection reverts the most recent selection of the draft on behalf of
+// the Master.
+func (service *LobbyService) UndoSelection(ctx context.Context, accessCode string, master profile.Profile) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, master.ID, func(lobby *Lobby) error {
+		err := lobby.Can(UndoAction, master)
+		if err != nil {
+			return err
+		}
+
+		return lobby.UndoSelection()
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
-	return lobbyResponse, nil
+	return service.cacheLobby(lobby), nil
 }
 
 func (service *LobbyService) SelectTeam(ctx context.Context, accessCode string, leader profile.Profile, teamID int) (*LobbyResponse, error) {
-	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
+	lobby, err := service.mutate(ctx, accessCode, leader.ID, func(lobby *Lobby) error {
+		return lobby.SelectTeam(leader, teamID)
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	if lobby == nil {
-		return nil, nil
-	}
+	return service.cacheLobby(lobby), nil
+}
+
+func (service *LobbyService) SelectPlayer(ctx context.Context, accessCode string, leader profile.Profile, playerID string) (*LobbyResponse, error) {
+	lobby, err := service.mutate(ctx, accessCode, leader.ID, func(lobby *Lobby) error {
+		return lobby.SelectPlayer(leader, playerID)
+	})
 
-	err = lobby.SelectTeam(leader, teamID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.repo.Update(ctx, lobby)
+	return service.cacheLobby(lobby), nil
+}
+
+// RestoreTurnTimers schedules the turn deadlines stored in the repository,
+// so turns keep expiring after a restart.
+func (service *LobbyService) RestoreTurnTimers(ctx context.Context) error {
+	lobbies, err := service.repo.FindWithTurnDeadline(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
-	return lobbyResponse, nil
+	for _, lobby := range lobbies {
+		service.scheduleTurn(lobby)
+	}
+
+	return nil
 }
 
-func (service *LobbyService) SelectPlayer(ctx context.Context, accessCode string, leader profile.Profile, playerID string) (*LobbyResponse, error) {
-	lobby, err := service.repo.FindByAccessCode(ctx, accessCode)
+func (service *LobbyService) scheduleTurn(lobby *Lobby) {
+	if lobby.ChooseControl == nil || lobby.ChooseControl.Deadline == 0 {
+		service.turns.cancel(lobby.AccessCode)
+		return
+	}
+
+	service.turns.schedule(lobby.AccessCode, lobby.ChooseControl.Deadline, service.expireTurn)
+}
+
+// expireTurn makes the automatic choice for a leader who let the turn run out.
+// Nothing happens when the turn was played or rescheduled in the meantime.
+func (service *LobbyService) expireTurn(accessCode string) {
+	lobby, err := service.mutate(context.Background(), accessCode, SystemActor, func(lobby *Lobby) error {
+		return lobby.AutoPick(time.Now())
+	})
+
 	if err != nil {
-		return nil, err
+		service.retryTurn(accessCode, err)
+		return
 	}
 
-	if lobby == nil {
-		return nil, nil
+	service.cacheLobby(lobby)
+}
+
+// retryTurn schedules the turn again after expireTurn failed, since the
+// timer may fire just before the deadline or the write fail, and the turn
+// must still expire. The stored lobby tells whether its turn still has a
+// deadline; the choice is never tried again sooner than turnRetryDelay.
+func (service *LobbyService) retryTurn(accessCode string, err error) {
+	if errors.Is(err, ErrLobbyNotFound) {
+		return
 	}
 
-	err = lobby.SelectPlayer(leader, playerID)
-	if err != nil {
-		return nil, err
+	if !errors.Is(err, ErrTurnNotExpired) {
+		log.Printf("turn of lobby %s did not expire, trying again: %v", accessCode, err)
+	}
+
+	retryAt := time.Now().Add(turnRetryDelay).Unix()
+
+	lobby, err := service.repo.FindByAccessCode(context.Background(), accessCode)
+	if errors.Is(err, ErrLobbyNotFound) {
+		return
 	}
 
-	err = service.repo.Update(ctx, lobby)
 	if err != nil {
-		return nil, err
+		service.turns.schedule(accessCode, retryAt, service.expireTurn)
+		return
+	}
+
+	// a turn played in the meantime was scheduled by its own write
+	if lobby == nil || lobby.ChooseControl == nil || lobby.ChooseControl.Deadline == 0 {
+		return
+	}
+
+	if lobby.ChooseControl.Deadline > retryAt {
+		retryAt = lobby.ChooseControl.Deadline
+	}
+
+	service.turns.schedule(accessCode, retryAt, service.expireTurn)
+}
+
+// mutate loads the lobby, applies fn, persists the result along with the
+// events it produced on behalf of actor, delivers and publishes the events
+// and schedules the deadline of the current turn. When another writer
+// updated the lobby in the meantime, the lobby is reloaded and fn is applied
+// again, up to maxUpdateAttempts times.
+func (service *LobbyService) mutate(ctx context.Context, accessCode string, actor string, fn func(lobby *Lobby) error) (*Lobby, error) {
+	var err error
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var lobby *Lobby
+
+		lobby, err = service.repo.FindByAccessCode(ctx, accessCode)
+		if err != nil {
+			return nil, err
+		}
+
+		if lobby == nil {
+			return nil, ErrLobbyNotFound
+		}
+
+		// the outbox holds the events of the previous write, usually
+		// delivered already, so only the new events are stored with this one
+		service.deliverOrLog(ctx, lobby)
+
+		err = fn(lobby)
+		if err != nil {
+			return nil, err
+		}
+
+		events := service.stage(lobby, actor, lobby.Version+1)
+
+		err = service.repo.Update(ctx, lobby)
+		if err == nil {
+			service.deliverOrLog(ctx, lobby)
+			service.publish(lobby, events)
+			service.scheduleTurn(lobby)
+			return lobby, nil
+		}
+
+		if !IsVersionConflict(err) {
+			return nil, err
+		}
 	}
 
+	return nil, err
+}
+
+// stage pulls the events of the lobby, stamps them with the actor and the
+// version the write about to be made produces and adds them to the outbox,
+// so they are stored in the same write as the lobby.
+func (service *LobbyService) stage(lobby *Lobby, actor string, version int) []LobbyEvent {
+	events := lobby.PullEvents()
+
+	for i := range events {
+		events[i].Actor = actor
+		events[i].Version = version
+		events[i].Index = i
+	}
+
+	lobby.Outbox = append(lobby.Outbox, events...)
+	return events
+}
+
+// deliver appends the outbox of the lobby to the event log, retrying a few
+// times, and empties it. The emptied outbox is only stored with the next
+// write, so the same events may be delivered again, which Append ignores.
+func (service *LobbyService) deliver(ctx context.Context, lobby *Lobby) error {
+	if len(lobby.Outbox) == 0 {
+		return nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= deliveryAttempts; attempt++ {
+		if err = service.history.Append(ctx, lobby.Outbox...); err == nil {
+			lobby.Outbox = nil
+			return nil
+		}
+
+		if attempt < deliveryAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * deliveryBackoff):
+			}
+		}
+	}
+
+	return err
+}
+
+// deliverOrLog delivers the outbox of a lobby already stored. The events are
+// safe in the outbox, so a failure is logged instead of failing the request.
+func (service *LobbyService) deliverOrLog(ctx context.Context, lobby *Lobby) {
+	if err := service.deliver(ctx, lobby); err != nil {
+		log.Printf("%d events of lobby %s stay in its outbox: %v", len(lobby.Outbox), lobby.AccessCode, err)
+	}
+}
+
+// publish sends the recorded events of the lobby followed by a snapshot of
+// its new state.
+func (service *LobbyService) publish(lobby *Lobby, events []LobbyEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	events = append(events, LobbyEvent{
+		Type:       LobbyUpdated,
+		AccessCode: lobby.AccessCode,
+		Version:    lobby.Version,
+		Timestamp:  time.Now().Unix(),
+		Payload:    ResponseFromLobby(lobby),
+	})
+
+	service.events.Publish(lobby.AccessCode, events...)
+
+	if lobby.Status == Finished || lobby.Status == Archived {
+		service.events.Retire(lobby.AccessCode)
+	}
+}
+
+func (service *LobbyService) cacheLobby(lobby *Lobby) *LobbyResponse {
 	lobbyResponse := ResponseFromLobby(lobby)
-	service.cache.Set(accessCode, lobbyResponse, cache.DefaultExpiration)
-	return lobbyResponse, nil
+	service.cache.Set(lobby.AccessCode, lobbyResponse, cache.DefaultExpiration)
+	return lobbyResponse
 }
@@ -2,13 +2,18 @@ package lobby
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
 )
 
+// LobbyRepositoryMock keeps copies of the lobbies in Memory, by access code,
+// and behaves as the real repositories: it passes lobbytest.Run.
 type LobbyRepositoryMock struct {
+	mu     sync.Mutex
 	Memory map[string]*Lobby
 }
 
@@ -19,27 +24,205 @@ func NewLobbyRepositoryMock() *LobbyRepositoryMock {
 }
 
 func (r *LobbyRepositoryMock) FindByAccessCode(ctx context.Context, accessCode string) (*Lobby, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	lobby, ok := r.Memory[accessCode]
 	if !ok {
-		return nil, nil
+		return nil, ErrLobbyNotFound
 	}
 
-	return lobby, nil
+	return cloneLobby(lobby), nil
 }
 
 func (r *LobbyRepositoryMock) Update(ctx context.Context, lobby *Lobby) error {
-	r.Memory[lobby.AccessCode] = lobby
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.Memory[lobby.AccessCode]
+	if !ok || stored.ID != lobby.ID || stored.Version != lobby.Version {
+		return &VersionConflictError{LobbyID: lobby.ID, Version: lobby.Version}
+	}
+
+	lobby.Version++
+	r.Memory[lobby.AccessCode] = cloneLobby(lobby)
 	return nil
 }
 
+func (r *LobbyRepositoryMock) FindWithTurnDeadline(ctx context.Context) ([]*Lobby, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lobbies := make([]*Lobby, 0)
+	for _, lobby := range r.Memory {
+		if lobby.ChooseControl != nil && lobby.ChooseControl.Deadline > 0 {
+			lobbies = append(lobbies, cloneLobby(lobby))
+		}
+	}
+
+	return lobbies, nil
+}
+
+func (r *LobbyRepositoryMock) List(ctx context.Context, filter LobbyFilter, page PageRequest) (LobbyPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lobbies := make([]*Lobby, 0, len(r.Memory))
+	for _, lobby := range r.Memory {
+		lobbies = append(lobbies, cloneLobby(lobby))
+	}
+
+	return PageOf(lobbies, filter, page)
+}
+
 func (r *LobbyRepositoryMock) Save(ctx context.Context, lobby *Lobby) error {
-	r.Memory[lobby.AccessCode] = lobby
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.Memory[lobby.AccessCode]; ok {
+		return ErrAccessCodeTaken
+	}
+
+	r.Memory[lobby.AccessCode] = cloneLobby(lobby)
+	return nil
+}
+
+// LobbyEventStoreMock keeps the events in memory and, as the real stores,
+// skips the ones already appended.
+type LobbyEventStoreMock struct {
+	mu     sync.Mutex
+	Events map[string][]LobbyEvent // by access code
+}
+
+func NewLobbyEventStoreMock() *LobbyEventStoreMock {
+	return &LobbyEventStoreMock{Events: make(map[string][]LobbyEvent)}
+}
+
+func (s *LobbyEventStoreMock) Append(ctx context.Context, events ...LobbyEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+next:
+	for _, event := range events {
+		for _, stored := range s.Events[event.AccessCode] {
+			if stored.Version == event.Version && stored.Index == event.Index {
+				continue next
+			}
+		}
+
+		s.Events[event.AccessCode] = append(s.Events[event.AccessCode], event)
+	}
+
 	return nil
 }
 
+func (s *LobbyEventStoreMock) Load(ctx context.Context, accessCode string) ([]LobbyEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]LobbyEvent(nil), s.Events[accessCode]...), nil
+}
+
+// cloneLobby copies the lobby without its pending events, so nothing the
+// caller changes reaches the stored lobby before Update.
+func cloneLobby(l *Lobby) *Lobby {
+	c := *l
+	c.events = nil
+	c.Master = cloneProfile(l.Master)
+	c.Players = cloneProfiles(l.Players)
+	c.Mentors = cloneProfiles(l.Mentors)
+	c.Departed = cloneProfiles(l.Departed)
+	c.Waitlist = cloneProfiles(l.Waitlist)
+	c.Kicked = append([]string(nil), l.Kicked...)
+	c.Outbox = append([]LobbyEvent(nil), l.Outbox...)
+	c.LeaderRule.HardSkills = append([]profile.HardSkill(nil), l.LeaderRule.HardSkills...)
+	c.LeaderRule.SoftSkills = append([]profile.SoftSkill(nil), l.LeaderRule.SoftSkills...)
+	c.SkillCatalogue.HardSkills = append([]SkillDefinition(nil), l.SkillCatalogue.HardSkills...)
+	c.SkillCatalogue.SoftSkills = append([]SkillDefinition(nil), l.SkillCatalogue.SoftSkills...)
+
+	c.StatusTimestamps = make(map[LobbyStatus]int64, len(l.StatusTimestamps))
+	for status, at := range l.StatusTimestamps {
+		c.StatusTimestamps[status] = at
+	}
+
+	c.Teams = make([]*Team, len(l.Teams))
+	for i, team := range l.Teams {
+		c.Teams[i] = &Team{
+			ID:      team.ID,
+			Mentor:  cloneProfile(team.Mentor),
+			Leader:  cloneProfile(team.Leader),
+			Players: cloneProfiles(team.Players),
+		}
+	}
+
+	c.DraftHistory = make([]DraftAction, len(l.DraftHistory))
+	for i, action := range l.DraftHistory {
+		action.Leader = cloneProfile(action.Leader)
+		action.Player = cloneProfile(action.Player)
+		c.DraftHistory[i] = action
+	}
+
+	if l.ChooseControl != nil {
+		chooseControl := *l.ChooseControl
+		chooseControl.ChoosingNow = cloneProfile(chooseControl.ChoosingNow)
+		c.ChooseControl = &chooseControl
+	}
+
+	return &c
+}
+
+func cloneProfiles(profiles []profile.Profile) []profile.Profile {
+	cloned := make([]profile.Profile, len(profiles))
+	for i, p := range profiles {
+		cloned[i] = cloneProfile(p)
+	}
+
+	return cloned
+}
+
+func cloneProfile(p profile.Profile) profile.Profile {
+	p.HardSkills = append([]profile.HardSkill(nil), p.HardSkills...)
+	p.SoftSkills = append([]profile.SoftSkill(nil), p.SoftSkills...)
+	p.HardSkillLevels = copyLevels(p.HardSkillLevels)
+	p.SoftSkillLevels = copyLevels(p.SoftSkillLevels)
+	return p
+}
+
+func copyLevels[S profile.HardSkill | profile.SoftSkill](levels map[S]profile.Proficiency) map[S]profile.Proficiency {
+	if levels == nil {
+		return nil
+	}
+
+	copied := make(map[S]profile.Proficiency, len(levels))
+	for skill, proficiency := range levels {
+		copied[skill] = proficiency
+	}
+
+	return copied
+}
+
+// ConflictingLobbyRepositoryMock reports a version conflict for the first
+// Conflicts updates, as if another writer had changed the lobby.
+type ConflictingLobbyRepositoryMock struct {
+	*LobbyRepositoryMock
+	Conflicts int
+	Updates   int
+}
+
+func (r *ConflictingLobbyRepositoryMock) Update(ctx context.Context, lobby *Lobby) error {
+	r.Updates++
+
+	if r.Conflicts > 0 {
+		r.Conflicts--
+		return &VersionConflictError{LobbyID: lobby.ID, Version: lobby.Version}
+	}
+
+	return r.LobbyRepositoryMock.Update(ctx, lobby)
+}
+
 func TestCreateLobby(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -68,7 +251,7 @@ func TestCreateLobby(t *testing.T) {
 
 func TestJoinLobby(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -113,7 +296,7 @@ func TestJoinLobby(t *testing.T) {
 
 func TestJoinLobbyWithMentor(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -159,7 +342,7 @@ func TestJoinLobbyWithMentor(t *testing.T) {
 
 func TestStartTeamCreationService(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -195,7 +378,7 @@ func TestStartTeamCreationService(t *testing.T) {
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, mentorProfile)
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, leaderProfile)
 
-	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode)
+	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode, master)
 
 	<-time.After(1 * time.Second)
 
@@ -216,7 +399,7 @@ func TestStartTeamCreationService(t *testing.T) {
 
 func TestSelectTeamService(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -252,7 +435,7 @@ func TestSelectTeamService(t *testing.T) {
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, mentorProfile)
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, leaderProfile)
 
-	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode)
+	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode, master)
 
 	<-time.After(1 * time.Second)
 
@@ -293,7 +476,7 @@ func TestSelectTeamService(t *testing.T) {
 
 func TestPlayerSelectService(t *testing.T) {
 	repo := NewLobbyRepositoryMock()
-	service := NewLobbyService(repo)
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
 
 	master := profile.Profile{
 		Name: "Master",
@@ -329,7 +512,7 @@ func TestPlayerSelectService(t *testing.T) {
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, mentorProfile)
 	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, leaderProfile)
 
-	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode)
+	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode, master)
 
 	<-time.After(1 * time.Second)
 
@@ -385,3 +568,329 @@ func TestPlayerSelectService(t *testing.T) {
 		t.Error("lobby status is not ReadyToStart")
 	}
 }
+
+func TestJoinLobby_RetriesOnVersionConflict(t *testing.T) {
+	repo := &ConflictingLobbyRepositoryMock{LobbyRepositoryMock: NewLobbyRepositoryMock()}
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.Profile{
+		Name: "Master",
+		Role: profile.Master,
+	}
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1)
+
+	repo.Conflicts = maxUpdateAttempts - 1
+
+	player := profile.Profile{
+		Name: "Player",
+		Role: profile.Player,
+	}
+
+	lobby, err := service.JoinLobby(context.Background(), lobby.AccessCode, player)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+
+	if repo.Updates != maxUpdateAttempts {
+		t.Errorf("Expected %d update attempts, got %d", maxUpdateAttempts, repo.Updates)
+	}
+
+	if len(lobby.Players) != 1 {
+		t.Errorf("Expected 1 player after retries, got %d", len(lobby.Players))
+	}
+}
+
+func TestJoinLobby_WhenRetriesAreExhausted(t *testing.T) {
+	repo := &ConflictingLobbyRepositoryMock{LobbyRepositoryMock: NewLobbyRepositoryMock()}
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.Profile{
+		Name: "Master",
+		Role: profile.Master,
+	}
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1)
+
+	repo.Conflicts = maxUpdateAttempts
+
+	player := profile.Profile{
+		Name: "Player",
+		Role: profile.Player,
+	}
+
+	_, err := service.JoinLobby(context.Background(), lobby.AccessCode, player)
+
+	if !IsVersionConflict(err) {
+		t.Errorf("Expected version conflict, got %v", err)
+	}
+
+	if repo.Updates != maxUpdateAttempts {
+		t.Errorf("Expected %d update attempts, got %d", maxUpdateAttempts, repo.Updates)
+	}
+}
+
+func TestJoinLobby_PublishesEvents(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.Profile{
+		Name: "Master",
+		Role: profile.Master,
+	}
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1)
+
+	subscription, _, err := service.SubscribeEvents(context.Background(), lobby.AccessCode, 0)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+		return
+	}
+	defer subscription.Close()
+
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, profile.NewMentor("Mentor", "avatar"))
+
+	event := <-subscription.Events
+
+	if event.Type != MentorJoined {
+		t.Errorf("Expected mentor_joined event, got %v", event.Type)
+	}
+
+	if event.AccessCode != lobby.AccessCode {
+		t.Errorf("Expected event access code to be %s, got %s", lobby.AccessCode, event.AccessCode)
+	}
+}
+
+func TestStartTeamCreationService_WhenNotMaster(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.NewMaster("Master", "avatar")
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1)
+
+	hardSkills := []profile.HardSkill{profile.English}
+	softSkills := []profile.SoftSkill{profile.Communication}
+	playerProfile := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+	playerProfile2 := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile2)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, profile.NewMentor("Mentor", "avatar"))
+
+	_, err := service.StartTeamCreation(context.Background(), lobby.AccessCode, playerProfile)
+
+	if !errors.Is(err, ErrNotMaster) {
+		t.Errorf("Expected ErrNotMaster, got %v", err)
+	}
+
+	lobby, _ = service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if lobby.Status != Waiting {
+		t.Errorf("Expected Status to be Waiting, got %v", lobby.Status)
+	}
+}
+
+func TestTurnTimerService(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.Profile{
+		Name: "Master",
+		Role: profile.Master,
+	}
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1, WithTurnTimeout(2*time.Second))
+
+	hardSkills := []profile.HardSkill{profile.English}
+	softSkills := []profile.SoftSkill{profile.Communication}
+	playerProfile := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+	playerProfile2 := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+	mentorProfile := profile.NewMentor("Mentor", "avatar")
+
+	leaderSoftSkill := []profile.SoftSkill{profile.Leadership}
+	leaderProfile := profile.NewPlayer("Leader", "avatar", hardSkills, leaderSoftSkill)
+
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile2)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, mentorProfile)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, leaderProfile)
+
+	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode, master)
+
+	<-time.After(200 * time.Millisecond)
+
+	lobby, _ = service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if lobby.Status != LeaderTeamSelect {
+		t.Errorf("Expected Status to be LeaderTeamSelect, got %v", lobby.Status)
+		return
+	}
+
+	if lobby.ChooseControl.Deadline == 0 {
+		t.Errorf("Expected ChooseControl to have a deadline")
+	}
+
+	<-time.After(3 * time.Second)
+
+	lobby, _ = service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if lobby.Status != PlayerSelect {
+		t.Errorf("Expected the expired turn to select a team, got status %v", lobby.Status)
+		return
+	}
+
+	if lobby.Teams[0].Leader.ID != leaderProfile.ID {
+		t.Errorf("Expected team 0 to be led by %s, got %s", leaderProfile.ID, lobby.Teams[0].Leader.ID)
+	}
+}
+
+func TestRestoreTurnTimers(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test", 1, 2, WithTurnTimeout(time.Minute))
+	lobby.Status = PlayerSelect
+	lobby.Teams = []*Team{{ID: 0, Leader: profile.Profile{ID: "leader", Role: profile.Leader}}}
+	lobby.Players = []profile.Profile{{ID: "player", Role: profile.Player}}
+	lobby.ChooseControl = &ChooseControl{
+		ChoosingNow: lobby.Teams[0].Leader,
+		Type:        SelectPlayer,
+		Deadline:    time.Now().Add(-time.Second).Unix(), // expired while the server was down
+	}
+
+	_ = repo.Save(context.Background(), lobby)
+
+	err := service.RestoreTurnTimers(context.Background())
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	<-time.After(500 * time.Millisecond)
+
+	response, _ := service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if response.Status != ReadyToStart {
+		t.Errorf("Expected Status to be ReadyToStart, got %v", response.Status)
+	}
+
+	if len(response.Teams[0].Players) != 1 || response.Teams[0].Players[0].ID != "player" {
+		t.Errorf("Expected player to be picked automatically, got %+v", response.Teams[0].Players)
+	}
+}
+
+func TestExpireTurn_BeforeDeadline(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test", 1, 2, WithTurnTimeout(time.Minute))
+	lobby.Status = PlayerSelect
+	lobby.Teams = []*Team{{ID: 0, Leader: profile.Profile{ID: "leader", Role: profile.Leader}}}
+	lobby.Players = []profile.Profile{{ID: "player", Role: profile.Player}}
+	lobby.ChooseControl = &ChooseControl{
+		ChoosingNow: lobby.Teams[0].Leader,
+		Type:        SelectPlayer,
+		Deadline:    time.Now().Unix() + 1,
+	}
+
+	_ = repo.Save(context.Background(), lobby)
+
+	// the timer fires a moment too early
+	service.expireTurn(lobby.AccessCode)
+
+	response, _ := service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if response.Status != PlayerSelect {
+		t.Fatalf("Expected the turn to wait for its deadline, got status %v", response.Status)
+	}
+
+	<-time.After(2500 * time.Millisecond)
+
+	response, _ = service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if response.Status != ReadyToStart {
+		t.Errorf("Expected the turn to expire at its deadline, got status %v", response.Status)
+	}
+}
+
+func TestBalanceTeamsService(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.NewMaster("Master", "avatar")
+
+	lobby, _ := service.CreateLobby(context.Background(), master, "Test", 1, 1, WithTeamFormation(BalancedFormation))
+
+	hardSkills := []profile.HardSkill{profile.English}
+	softSkills := []profile.SoftSkill{profile.Communication}
+	playerProfile := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+	playerProfile2 := profile.NewPlayer("Player", "avatar", hardSkills, softSkills)
+	leaderProfile := profile.NewPlayer("Leader", "avatar", hardSkills, []profile.SoftSkill{profile.Leadership})
+
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, playerProfile2)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, leaderProfile)
+	_, _ = service.JoinLobby(context.Background(), lobby.AccessCode, profile.NewMentor("Mentor", "avatar"))
+
+	_, _ = service.StartTeamCreation(context.Background(), lobby.AccessCode, master)
+
+	<-time.After(1 * time.Second)
+
+	lobby, _ = service.GetLobby(context.Background(), lobby.AccessCode)
+
+	if lobby.Status != TeamsCreated {
+		t.Errorf("Expected Status to be TeamsCreated, got %v", lobby.Status)
+	}
+
+	_, _, err := service.BalanceTeams(context.Background(), lobby.AccessCode, leaderProfile, 1)
+
+	if !errors.Is(err, ErrNotMaster) {
+		t.Errorf("Expected ErrNotMaster, got %v", err)
+	}
+
+	lobby, coverage, err := service.BalanceTeams(context.Background(), lobby.AccessCode, master, 1)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if lobby.Status != ReadyToStart {
+		t.Errorf("Expected Status to be ReadyToStart, got %v", lobby.Status)
+	}
+
+	if len(coverage) != 1 || coverage[0].Members != 3 {
+		t.Errorf("Expected one team with 3 members, got %+v", coverage)
+	}
+}
+
+func TestChangeStatusService_WhenNotMaster(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+
+	master := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(master, "Test Lobby", 1, 2)
+	lobby.Status = ReadyToStart
+	_ = repo.Save(context.Background(), lobby)
+
+	_, err := service.ChangeStatus(context.Background(), lobby.AccessCode, profile.NewMaster("Other", "avatar"), StartAction)
+
+	if !errors.Is(err, ErrNotMaster) {
+		t.Errorf("Expected ErrNotMaster, got %v", err)
+	}
+
+	response, err := service.ChangeStatus(context.Background(), lobby.AccessCode, master, StartAction)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Status != InProgress {
+		t.Errorf("Expected Status to be InProgress, got %v", response.Status)
+	}
+
+	if response.StatusTimestamps[InProgress] == 0 {
+		t.Errorf("Expected a timestamp for InProgress")
+	}
+}
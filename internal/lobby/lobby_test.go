@@ -1,8 +1,10 @@
 package lobby
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
 )
@@ -126,6 +128,7 @@ func TestJoinLobby_WithPlayerAndLeader(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
+	leaderProfile.Role = profile.Leader // GDP makes a leader under the default leader rule
 	if !reflect.DeepEqual(lobby.getAllLeaders(), []profile.Profile{leaderProfile}) {
 		t.Errorf("Expected Players to be %+v, got %+v", []profile.Profile{leaderProfile}, lobby.Players)
 	}
@@ -241,8 +244,8 @@ func TestCreateTeams(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	if leaderProfile.Role != profile.Leader {
-		t.Errorf("Expected Role to be Leader, got %v", leaderProfile.Role)
+	if leader := lobby.getPlayer(leaderProfile.ID); leader.Role != profile.Leader {
+		t.Errorf("Expected Role to be Leader, got %v", leader.Role)
 	}
 
 	if lobby.Status != TeamsCreated {
@@ -592,3 +595,124 @@ func TestPlayerSelect(t *testing.T) {
 		t.Errorf("Expected next leader to be %+v, got %+v", secondPriorityProfile, lobby.ChooseControl.ChoosingNow)
 	}
 }
+
+func TestSelectTeam_WhenNotLeaderTurn(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	mentorProfile := profile.NewMentor("Mentor", "avatar")
+	mentorProfile2 := profile.NewMentor("Mentor", "avatar")
+
+	firstLeaderProfile := profile.NewPlayer("First Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership})
+	secondLeaderProfile := profile.NewPlayer("Second Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership})
+
+	_ = lobby.Join(mentorProfile)
+	_ = lobby.Join(mentorProfile2)
+	_ = lobby.Join(firstLeaderProfile)
+	_ = lobby.Join(secondLeaderProfile)
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	_ = lobby.StartLeaderTeamSelection()
+
+	err := lobby.SelectTeam(secondLeaderProfile, 0)
+
+	if !errors.Is(err, ErrNotLeaderTurn) {
+		t.Errorf("Expected ErrNotLeaderTurn, got %v", err)
+	}
+
+	err = lobby.SelectTeam(firstLeaderProfile, 5)
+
+	if !errors.Is(err, ErrInvalidTeam) {
+		t.Errorf("Expected ErrInvalidTeam, got %v", err)
+	}
+}
+
+func TestAutoPick(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2, WithTurnTimeout(time.Minute))
+
+	mentorProfile := profile.NewMentor("Mentor", "avatar")
+	mentorProfile2 := profile.NewMentor("Mentor", "avatar")
+
+	firstLeaderProfile := profile.NewPlayer("First Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership, profile.Communication})
+	secondLeaderProfile := profile.NewPlayer("Second Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Communication})
+
+	sameSkillsProfile := profile.NewPlayer("Same Skills", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	newSkillsProfile := profile.NewPlayer("New Skills", "avatar", []profile.HardSkill{profile.Programming}, []profile.SoftSkill{profile.Creativity})
+
+	_ = lobby.Join(mentorProfile)
+	_ = lobby.Join(mentorProfile2)
+	_ = lobby.Join(firstLeaderProfile)
+	_ = lobby.Join(secondLeaderProfile)
+	_ = lobby.Join(sameSkillsProfile)
+	_ = lobby.Join(newSkillsProfile)
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	_ = lobby.StartLeaderTeamSelection()
+
+	if lobby.ChooseControl.Deadline == 0 {
+		t.Errorf("Expected ChooseControl to have a deadline")
+	}
+
+	err := lobby.AutoPick(time.Now())
+
+	if !errors.Is(err, ErrTurnNotExpired) {
+		t.Errorf("Expected ErrTurnNotExpired, got %v", err)
+	}
+
+	err = lobby.AutoPick(time.Now().Add(2 * time.Minute))
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if lobby.Teams[0].Leader.ID != firstLeaderProfile.ID {
+		t.Errorf("Expected lowest free team to go to %+v, got %+v", firstLeaderProfile, lobby.Teams[0].Leader)
+	}
+
+	_ = lobby.SelectTeam(secondLeaderProfile, 1)
+
+	if lobby.Status != PlayerSelect {
+		t.Errorf("Expected Status to be PlayerSelect, got %v", lobby.Status)
+	}
+
+	err = lobby.AutoPick(time.Now().Add(2 * time.Minute))
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Teams[0].Players) != 1 || lobby.Teams[0].Players[0].ID != newSkillsProfile.ID {
+		t.Errorf("Expected the player adding new skills to be picked, got %+v", lobby.Teams[0].Players)
+	}
+}
+
+func TestSelectTeam_WhenTeamIsTaken(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+
+	firstLeaderProfile := profile.NewPlayer("First Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership})
+	secondLeaderProfile := profile.NewPlayer("Second Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership})
+	thirdLeaderProfile := profile.NewPlayer("Third Leader", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Communication})
+
+	_ = lobby.Join(firstLeaderProfile)
+	_ = lobby.Join(secondLeaderProfile)
+	_ = lobby.Join(thirdLeaderProfile)
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	_ = lobby.StartLeaderTeamSelection()
+
+	_ = lobby.SelectTeam(firstLeaderProfile, 0)
+	err := lobby.SelectTeam(secondLeaderProfile, 0)
+
+	if !errors.Is(err, ErrTeamAlreadyTaken) {
+		t.Errorf("Expected ErrTeamAlreadyTaken, got %v", err)
+	}
+}
@@ -0,0 +1,593 @@
+// Package lobbytest checks that a lobby_.LobbyRepository behaves as the
+// services expect, so every implementation runs the same suite.
+package lobbytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	lobby_ "github.com/paq-devs/paq-be-rpg/internal/lobby"
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// Factory returns an empty repository for each test.
+type Factory func(t *testing.T) lobby_.LobbyRepository
+
+func Run(t *testing.T, factory Factory) {
+	t.Run("SaveAndFind", func(t *testing.T) { testSaveAndFind(t, factory(t)) })
+	t.Run("SaveAndFindWaiting", func(t *testing.T) { testSaveAndFindWaiting(t, factory(t)) })
+	t.Run("SaveTakenAccessCode", func(t *testing.T) { testSaveTakenAccessCode(t, factory(t)) })
+	t.Run("FindMissing", func(t *testing.T) { testFindMissing(t, factory(t)) })
+	t.Run("FindReturnsCopies", func(t *testing.T) { testFindReturnsCopies(t, factory(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, factory(t)) })
+	t.Run("UpdateStale", func(t *testing.T) { testUpdateStale(t, factory(t)) })
+	t.Run("UpdateMissing", func(t *testing.T) { testUpdateMissing(t, factory(t)) })
+	t.Run("ConcurrentUpdates", func(t *testing.T) { testConcurrentUpdates(t, factory(t)) })
+	t.Run("ConcurrentRetries", func(t *testing.T) { testConcurrentRetries(t, factory(t)) })
+	t.Run("FindWithTurnDeadline", func(t *testing.T) { testFindWithTurnDeadline(t, factory(t)) })
+	t.Run("ListFilters", func(t *testing.T) { testListFilters(t, factory(t)) })
+	t.Run("ListPages", func(t *testing.T) { testListPages(t, factory(t)) })
+	t.Run("ListInvalidCursor", func(t *testing.T) { testListInvalidCursor(t, factory(t)) })
+}
+
+// newDraftingLobby returns a lobby in the middle of its draft that sets every
+// field a repository stores but the waitlist, which is dismissed when the
+// lobby closes: custom settings, a departed and a kicked profile, skill levels
+// with notes, teams with players, the turn of a leader, with a deadline, to
+// pick a player and an outbox with every event the lobby produced.
+func newDraftingLobby(t *testing.T) *lobby_.Lobby {
+	t.Helper()
+
+	catalogue := lobby_.DefaultSkillCatalogue()
+	catalogue.HardSkills[0].Weight = 2
+	catalogue.SoftSkills[0].Category = "communication"
+
+	master := profile.NewMaster("Master", "avatar")
+	lobby := lobby_.NewLobby(master, "Test Lobby", 2, 2,
+		lobby_.WithCapacity(lobby_.Capacity{MaxPlayers: 5, MaxMentors: 2, MinPlayersPerTeam: 1, MaxPlayersPerTeam: 4}),
+		lobby_.WithTurnTimeout(time.Minute),
+		lobby_.WithDraftOrder(lobby_.Snake),
+		lobby_.WithPriorityStrategy(lobby_.WeightedPriority{
+			HardSkills: map[profile.HardSkill]int{profile.GDP: 5},
+			SoftSkills: map[profile.SoftSkill]int{profile.Leadership: 3},
+		}),
+		lobby_.WithLeaderRule(lobby_.LeaderRule{Mode: lobby_.AnyOfSkills, HardSkills: []profile.HardSkill{profile.GDP}}),
+		lobby_.WithSkillCatalogue(catalogue),
+	)
+
+	expert := profile.NewPlayer("Player 3", "avatar", []profile.HardSkill{profile.English, profile.Programming}, []profile.SoftSkill{profile.Communication})
+	expert.SetHardSkillLevel(profile.Programming, profile.Proficiency{Level: 5, Notes: "ships every week"})
+	expert.SetSoftSkillLevel(profile.Communication, profile.Proficiency{Level: 1})
+
+	departed := profile.NewPlayer("Departed", "avatar", []profile.HardSkill{profile.Design}, []profile.SoftSkill{profile.Empathy})
+	kicked := profile.NewPlayer("Kicked", "avatar", []profile.HardSkill{profile.Design}, []profile.SoftSkill{profile.Empathy})
+
+	members := []profile.Profile{
+		profile.NewMentor("Mentor 1", "avatar"),
+		profile.NewMentor("Mentor 2", "avatar"),
+		profile.NewPlayer("Player 1", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Leadership}),
+		profile.NewPlayer("Player 2", "avatar", []profile.HardSkill{profile.GDP, profile.English}, []profile.SoftSkill{profile.Organization}),
+		expert,
+		departed,
+		kicked,
+		profile.NewPlayer("Player 4", "avatar", []profile.HardSkill{profile.Marketing}, []profile.SoftSkill{profile.Creativity}),
+		profile.NewPlayer("Player 5", "avatar", []profile.HardSkill{profile.IA}, []profile.SoftSkill{profile.Proactivity}),
+	}
+
+	for _, member := range members {
+		if err := lobby.Join(member); err != nil {
+			t.Fatalf("Expected %s to join, got %v", member.Name, err)
+		}
+	}
+
+	must(t, lobby.Leave(departed.ID))
+	must(t, lobby.Kick(master.ID, kicked.ID))
+
+	must(t, lobby.StartTeamCreation())
+	must(t, lobby.CreateTeams())
+	must(t, lobby.StartLeaderTeamSelection())
+
+	for _, team := range lobby.Teams {
+		must(t, lobby.SelectTeam(lobby.ChooseControl.ChoosingNow, team.ID))
+	}
+
+	must(t, lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, expert.ID))
+
+	if len(lobby.Departed) == 0 || len(lobby.Kicked) == 0 {
+		t.Fatalf("Expected a departed and a kicked profile, got %+v and %+v", lobby.Departed, lobby.Kicked)
+	}
+
+	lobby.Outbox = lobby.PullEvents()
+	for i := range lobby.Outbox {
+		lobby.Outbox[i].Actor = master.ID
+		lobby.Outbox[i].Version = lobby.Version + 1
+		lobby.Outbox[i].Index = i
+	}
+
+	return lobby
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+// find returns the stored lobby, failing the test when it cannot be read.
+func find(t *testing.T, repository lobby_.LobbyRepository, accessCode string) *lobby_.Lobby {
+	t.Helper()
+
+	lobby, err := repository.FindByAccessCode(context.Background(), accessCode)
+	must(t, err)
+
+	if lobby == nil {
+		t.Fatalf("Expected lobby %s, got nil", accessCode)
+	}
+
+	return lobby
+}
+
+func expectSameState(t *testing.T, expected *lobby_.Lobby, got *lobby_.Lobby) {
+	t.Helper()
+
+	if !got.SameState(expected) {
+		t.Errorf("Expected the stored lobby to be %+v, got %+v", lobby_.ResponseFromLobby(expected), lobby_.ResponseFromLobby(got))
+	}
+
+	if got.Version != expected.Version {
+		t.Errorf("Expected Version to be %d, got %d", expected.Version, got.Version)
+	}
+}
+
+func testSaveAndFind(t *testing.T, repository lobby_.LobbyRepository) {
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(context.Background(), lobby))
+
+	found := find(t, repository, lobby.AccessCode)
+	expectSameState(t, lobby, found)
+
+	// SameState covers every field, these checks only point at the part that
+	// did not read back
+	if found.ChooseControl == nil {
+		t.Fatalf("Expected ChooseControl to be %+v, got nil", lobby.ChooseControl)
+	}
+
+	if found.ChooseControl.ChoosingNow.ID != lobby.ChooseControl.ChoosingNow.ID || found.ChooseControl.Type != lobby.ChooseControl.Type || found.ChooseControl.Deadline != lobby.ChooseControl.Deadline {
+		t.Errorf("Expected ChooseControl to be %+v, got %+v", lobby.ChooseControl, found.ChooseControl)
+	}
+
+	if len(found.Teams) != len(lobby.Teams) {
+		t.Fatalf("Expected %d teams, got %d", len(lobby.Teams), len(found.Teams))
+	}
+
+	for i, team := range lobby.Teams {
+		got := found.Teams[i]
+		if got.ID != team.ID || got.Leader.ID != team.Leader.ID || got.Mentor.ID != team.Mentor.ID || len(got.Players) != len(team.Players) {
+			t.Errorf("Expected team %d to be %+v, got %+v", i, lobby_.ResponseFromTeam(team), lobby_.ResponseFromTeam(got))
+			continue
+		}
+
+		for j, player := range team.Players {
+			if got.Players[j].ID != player.ID || got.Players[j].HardSkillLevel(profile.Programming) != player.HardSkillLevel(profile.Programming) {
+				t.Errorf("Expected player %d of team %d to be %+v, got %+v", j, i, player, got.Players[j])
+			}
+		}
+	}
+
+	if len(found.DraftHistory) != len(lobby.DraftHistory) {
+		t.Errorf("Expected %d draft actions, got %d", len(lobby.DraftHistory), len(found.DraftHistory))
+	}
+
+	expectSameOutbox(t, lobby.Outbox, found.Outbox)
+}
+
+// expectSameOutbox compares the events as the API sends them, which is how
+// their payloads are meant to read back.
+func expectSameOutbox(t *testing.T, expected []lobby_.LobbyEvent, got []lobby_.LobbyEvent) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d events in the outbox, got %d", len(expected), len(got))
+	}
+
+	for i, event := range expected {
+		want, err := json.Marshal(event)
+		must(t, err)
+
+		found, err := json.Marshal(got[i])
+		must(t, err)
+
+		if string(found) != string(want) {
+			t.Errorf("Expected event %d of the outbox to be %s, got %s", i, want, found)
+		}
+	}
+}
+
+func testSaveAndFindWaiting(t *testing.T, repository lobby_.LobbyRepository) {
+	lobby := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Waiting Lobby", 1, 1, lobby_.WithCapacity(lobby_.Capacity{MaxPlayers: 1}))
+	must(t, lobby.Join(profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.IA}, []profile.SoftSkill{profile.Collaboration})))
+	must(t, lobby.Join(profile.NewPlayer("Waitlisted", "avatar", []profile.HardSkill{profile.IA}, []profile.SoftSkill{profile.Collaboration})))
+	lobby.PullEvents()
+	must(t, repository.Save(context.Background(), lobby))
+
+	found := find(t, repository, lobby.AccessCode)
+	expectSameState(t, lobby, found)
+
+	if len(found.Waitlist) != 1 || found.Waitlist[0].Name != "Waitlisted" {
+		t.Errorf("Expected the waitlisted player, got %+v", found.Waitlist)
+	}
+
+	if found.ChooseControl != nil {
+		t.Errorf("Expected no ChooseControl, got %+v", found.ChooseControl)
+	}
+
+	if len(found.Teams) != 0 {
+		t.Errorf("Expected no teams, got %d", len(found.Teams))
+	}
+}
+
+func testSaveTakenAccessCode(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+
+	first := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "First", 1, 1, lobby_.WithAccessCode("ABC234"))
+	must(t, repository.Save(ctx, first))
+
+	second := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Second", 1, 1, lobby_.WithAccessCode("ABC234"))
+	if err := repository.Save(ctx, second); !errors.Is(err, lobby_.ErrAccessCodeTaken) {
+		t.Errorf("Expected ErrAccessCodeTaken, got %v", err)
+	}
+
+	if found := find(t, repository, "ABC234"); found.ID != first.ID {
+		t.Errorf("Expected lobby %s, got %s", first.ID, found.ID)
+	}
+}
+
+func testFindMissing(t *testing.T, repository lobby_.LobbyRepository) {
+	found, err := repository.FindByAccessCode(context.Background(), "missing")
+
+	if !errors.Is(err, lobby_.ErrLobbyNotFound) {
+		t.Errorf("Expected ErrLobbyNotFound, got %v", err)
+	}
+
+	if found != nil {
+		t.Errorf("Expected no lobby, got %+v", found)
+	}
+}
+
+func testFindReturnsCopies(t *testing.T, repository lobby_.LobbyRepository) {
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(context.Background(), lobby))
+
+	found := find(t, repository, lobby.AccessCode)
+	found.Name = "Changed without Update"
+	found.Players[0].HardSkills[0] = profile.Design
+	found.Teams[0].Players = nil
+	found.ChooseControl.Deadline = 0
+
+	expectSameState(t, lobby, find(t, repository, lobby.AccessCode))
+}
+
+func testUpdate(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(ctx, lobby))
+
+	version := lobby.Version
+	next := lobby.ChooseControl.ChoosingNow
+	must(t, lobby.SelectPlayer(next, lobby.Players[len(lobby.Players)-1].ID))
+	must(t, repository.Update(ctx, lobby))
+
+	if lobby.Version != version+1 {
+		t.Errorf("Expected Version to be %d, got %d", version+1, lobby.Version)
+	}
+
+	expectSameState(t, lobby, find(t, repository, lobby.AccessCode))
+}
+
+func testUpdateStale(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(ctx, lobby))
+
+	stale := find(t, repository, lobby.AccessCode)
+
+	lobby.Name = "First write"
+	must(t, repository.Update(ctx, lobby))
+
+	stale.Name = "Second write"
+	var conflict *lobby_.VersionConflictError
+	if err := repository.Update(ctx, stale); !errors.As(err, &conflict) {
+		t.Errorf("Expected a VersionConflictError, got %v", err)
+	}
+
+	if found := find(t, repository, lobby.AccessCode); found.Name != "First write" {
+		t.Errorf("Expected Name to be First write, got %s", found.Name)
+	}
+}
+
+func testUpdateMissing(t *testing.T, repository lobby_.LobbyRepository) {
+	lobby := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Never Saved", 1, 1)
+
+	var conflict *lobby_.VersionConflictError
+	if err := repository.Update(context.Background(), lobby); !errors.As(err, &conflict) {
+		t.Errorf("Expected a VersionConflictError for a lobby never saved, got %v", err)
+	}
+
+	if _, err := repository.FindByAccessCode(context.Background(), lobby.AccessCode); !errors.Is(err, lobby_.ErrLobbyNotFound) {
+		t.Errorf("Expected ErrLobbyNotFound after the update, got %v", err)
+	}
+}
+
+// testConcurrentUpdates races writers that all read the same version: only
+// one of them may win.
+func testConcurrentUpdates(t *testing.T, repository lobby_.LobbyRepository) {
+	const writers = 8
+
+	ctx := context.Background()
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(ctx, lobby))
+
+	copies := make([]*lobby_.Lobby, writers)
+	for i := range copies {
+		copies[i] = find(t, repository, lobby.AccessCode)
+		copies[i].Name = fmt.Sprintf("Writer %d", i)
+	}
+
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := range copies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repository.Update(ctx, copies[i])
+		}(i)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		var conflict *lobby_.VersionConflictError
+		switch {
+		case err == nil && winner == -1:
+			winner = i
+		case err == nil:
+			t.Errorf("Expected only one update to succeed, writers %d and %d did", winner, i)
+		case !errors.As(err, &conflict):
+			t.Errorf("Expected a VersionConflictError, got %v", err)
+		}
+	}
+
+	if winner == -1 {
+		t.Fatalf("Expected one update to succeed, got %v", errs)
+	}
+
+	found := find(t, repository, lobby.AccessCode)
+	if found.Name != copies[winner].Name || found.Version != lobby.Version+1 {
+		t.Errorf("Expected %s at version %d, got %s at version %d", copies[winner].Name, lobby.Version+1, found.Name, found.Version)
+	}
+}
+
+// testConcurrentRetries has writers reload and retry on conflicts, as
+// LobbyService does: no write may be lost.
+func testConcurrentRetries(t *testing.T, repository lobby_.LobbyRepository) {
+	const writers = 8
+
+	ctx := context.Background()
+	lobby := newDraftingLobby(t)
+	must(t, repository.Save(ctx, lobby))
+
+	errs := make(chan error, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for {
+				current, err := repository.FindByAccessCode(ctx, lobby.AccessCode)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				current.Kicked = append(current.Kicked, fmt.Sprintf("writer-%d", i))
+
+				var conflict *lobby_.VersionConflictError
+				err = repository.Update(ctx, current)
+				if errors.As(err, &conflict) {
+					continue
+				}
+
+				if err != nil {
+					errs <- err
+				}
+				return
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	found := find(t, repository, lobby.AccessCode)
+	if found.Version != lobby.Version+writers {
+		t.Errorf("Expected Version to be %d, got %d", lobby.Version+writers, found.Version)
+	}
+
+	if len(found.Kicked) != len(lobby.Kicked)+writers {
+		t.Errorf("Expected %d kicked profiles, got %+v", len(lobby.Kicked)+writers, found.Kicked)
+	}
+}
+
+func testFindWithTurnDeadline(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+
+	drafting := newDraftingLobby(t)
+	must(t, repository.Save(ctx, drafting))
+
+	waiting := lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Waiting Lobby", 1, 1)
+	must(t, repository.Save(ctx, waiting))
+
+	lobbies, err := repository.FindWithTurnDeadline(ctx)
+	must(t, err)
+
+	if len(lobbies) != 1 || lobbies[0].ID != drafting.ID {
+		t.Fatalf("Expected only lobby %s, got %d lobbies", drafting.ID, len(lobbies))
+	}
+
+	expectSameState(t, drafting, lobbies[0])
+}
+
+// saveCreatedAt stores the lobby as if it was created at the unix timestamp.
+func saveCreatedAt(t *testing.T, repository lobby_.LobbyRepository, lobby *lobby_.Lobby, createdAt int64) *lobby_.Lobby {
+	t.Helper()
+
+	lobby.CreatedAt = createdAt
+	lobby.PullEvents()
+	must(t, repository.Save(context.Background(), lobby))
+	return lobby
+}
+
+// expectListed checks the lobbies are the expected ones, in order.
+func expectListed(t *testing.T, expected []*lobby_.Lobby, got []*lobby_.Lobby) {
+	t.Helper()
+
+	ids := func(lobbies []*lobby_.Lobby) []string {
+		listed := make([]string, len(lobbies))
+		for i, lobby := range lobbies {
+			listed[i] = lobby.Name + " " + lobby.ID
+		}
+		return listed
+	}
+
+	if fmt.Sprint(ids(got)) != fmt.Sprint(ids(expected)) {
+		t.Errorf("Expected lobbies %v, got %v", ids(expected), ids(got))
+	}
+}
+
+func testListFilters(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+
+	master := profile.NewMaster("Master", "avatar")
+	quest := saveCreatedAt(t, repository, lobby_.NewLobby(master, "Dragon Quest", 1, 1), 1000)
+	crawl := saveCreatedAt(t, repository, lobby_.NewLobby(master, "dungeon crawl", 1, 1), 2000)
+	slayers := saveCreatedAt(t, repository, lobby_.NewLobby(profile.NewMaster("Other", "avatar"), "DRAGON Slayers 100%", 1, 1), 3000)
+	drafting := saveCreatedAt(t, repository, newDraftingLobby(t), 4000)
+
+	cases := []struct {
+		name     string
+		filter   lobby_.LobbyFilter
+		expected []*lobby_.Lobby
+	}{
+		{"everything", lobby_.LobbyFilter{}, []*lobby_.Lobby{drafting, slayers, crawl, quest}},
+		{"status", lobby_.LobbyFilter{Status: drafting.Status}, []*lobby_.Lobby{drafting}},
+		{"master", lobby_.LobbyFilter{MasterID: master.ID}, []*lobby_.Lobby{crawl, quest}},
+		{"name ignoring case", lobby_.LobbyFilter{Name: "dRaGoN"}, []*lobby_.Lobby{slayers, quest}},
+		{"name with wildcards", lobby_.LobbyFilter{Name: "0%"}, []*lobby_.Lobby{slayers}},
+		{"name with special characters", lobby_.LobbyFilter{Name: "n_c"}, []*lobby_.Lobby{}},
+		{"created from", lobby_.LobbyFilter{CreatedFrom: 2000}, []*lobby_.Lobby{drafting, slayers, crawl}},
+		{"created to", lobby_.LobbyFilter{CreatedTo: 2000}, []*lobby_.Lobby{quest}},
+		{"created between", lobby_.LobbyFilter{CreatedFrom: 1500, CreatedTo: 3500}, []*lobby_.Lobby{slayers, crawl}},
+		{"every field", lobby_.LobbyFilter{Status: lobby_.Waiting, MasterID: master.ID, Name: "crawl", CreatedFrom: 2000, CreatedTo: 2001}, []*lobby_.Lobby{crawl}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			page, err := repository.List(ctx, c.filter, lobby_.PageRequest{})
+			must(t, err)
+
+			expectListed(t, c.expected, page.Lobbies)
+
+			if page.NextCursor != "" {
+				t.Errorf("Expected no next cursor, got %s", page.NextCursor)
+			}
+		})
+	}
+
+	page, err := repository.List(ctx, lobby_.LobbyFilter{Status: drafting.Status}, lobby_.PageRequest{})
+	must(t, err)
+
+	if len(page.Lobbies) == 1 {
+		expectSameState(t, drafting, page.Lobbies[0])
+	}
+}
+
+func testListPages(t *testing.T, repository lobby_.LobbyRepository) {
+	ctx := context.Background()
+
+	master := profile.NewMaster("Master", "avatar")
+	lobbies := make([]*lobby_.Lobby, 0)
+	for i, createdAt := range []int64{1000, 2000, 2000, 2000, 3000} { // lobbies created in the same second are ordered by ID
+		lobbies = append(lobbies, saveCreatedAt(t, repository, lobby_.NewLobby(master, fmt.Sprintf("Lobby %d", i), 1, 1), createdAt))
+	}
+	saveCreatedAt(t, repository, lobby_.NewLobby(profile.NewMaster("Other", "avatar"), "Other", 1, 1), 2500)
+
+	sort.Slice(lobbies, func(i, j int) bool {
+		if lobbies[i].CreatedAt != lobbies[j].CreatedAt {
+			return lobbies[i].CreatedAt > lobbies[j].CreatedAt
+		}
+		return lobbies[i].ID > lobbies[j].ID
+	})
+
+	filter := lobby_.LobbyFilter{MasterID: master.ID}
+	request := lobby_.PageRequest{Limit: 2}
+	listed := make([]*lobby_.Lobby, 0)
+
+	for pages := 1; ; pages++ {
+		page, err := repository.List(ctx, filter, request)
+		must(t, err)
+
+		if len(page.Lobbies) > request.Limit {
+			t.Fatalf("Expected at most %d lobbies, got %d", request.Limit, len(page.Lobbies))
+		}
+
+		listed = append(listed, page.Lobbies...)
+
+		if page.NextCursor == "" {
+			if pages != 3 {
+				t.Errorf("Expected 3 pages, got %d", pages)
+			}
+			break
+		}
+
+		if pages == 3 {
+			t.Fatalf("Expected no next cursor on the last page, got %s", page.NextCursor)
+		}
+
+		request.Cursor = page.NextCursor
+	}
+
+	expectListed(t, lobbies, listed)
+
+	page, err := repository.List(ctx, lobby_.LobbyFilter{}, lobby_.PageRequest{Limit: 6})
+	must(t, err)
+
+	if len(page.Lobbies) != 6 || page.NextCursor != "" {
+		t.Errorf("Expected a single full page, got %d lobbies and next cursor %q", len(page.Lobbies), page.NextCursor)
+	}
+}
+
+func testListInvalidCursor(t *testing.T, repository lobby_.LobbyRepository) {
+	saveCreatedAt(t, repository, lobby_.NewLobby(profile.NewMaster("Master", "avatar"), "Lobby", 1, 1), 1000)
+
+	page, err := repository.List(context.Background(), lobby_.LobbyFilter{}, lobby_.PageRequest{Cursor: "not a cursor"})
+
+	if !errors.Is(err, lobby_.ErrInvalidPage) {
+		t.Errorf("Expected ErrInvalidPage, got %v", err)
+	}
+
+	if len(page.Lobbies) != 0 {
+		t.Errorf("Expected no lobbies, got %d", len(page.Lobbies))
+	}
+}
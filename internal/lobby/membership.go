@@ -0,0 +1,149 @@
+package lobby
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+// Leave removes a player or mentor from a lobby still waiting for players,
+// promoting waitlisted profiles into the freed spot. The profile is kept
+// aside, so Rejoin can restore it with its original JoinTimestamp. Leaving
+// the waitlist just drops the profile from it.
+func (l *Lobby) Leave(profileID string) error {
+	if err := l.Can(LeaveAction, profile.Profile{ID: profileID}); err != nil {
+		return err
+	}
+
+	if p := l.removeFromWaitlist(profileID); p != nil {
+		l.emit(ProfileLeft, ResponseFromProfile(p))
+		return nil
+	}
+
+	p := l.removeMember(profileID)
+	l.Departed = append(l.Departed, *p)
+	l.emit(ProfileLeft, ResponseFromProfile(p))
+	l.promoteFromWaitlist()
+	return nil
+}
+
+// Kick removes a player, mentor or waitlisted profile on behalf of the Master. Kicked profiles
+// can neither rejoin nor join again with the same ID.
+func (l *Lobby) Kick(masterID string, profileID string) error {
+	if err := l.Can(KickAction, profile.Profile{ID: masterID}); err != nil {
+		return err
+	}
+
+	p := l.removeMember(profileID)
+	if p == nil {
+		p = l.removeDeparted(profileID)
+	}
+
+	if p == nil {
+		p = l.removeFromWaitlist(profileID)
+	}
+
+	if p == nil {
+		return ErrProfileNotInLobby
+	}
+
+	l.Kicked = append(l.Kicked, profileID)
+	l.emit(ProfileKicked, ResponseFromProfile(p))
+	l.promoteFromWaitlist()
+	return nil
+}
+
+// Rejoin restores a profile that left the lobby, keeping the JoinTimestamp
+// it got when it first joined, since it drives DefinePriorities.
+func (l *Lobby) Rejoin(profileID string) error {
+	if err := l.Can(RejoinAction, profile.Profile{ID: profileID}); err != nil {
+		return err
+	}
+
+	p := l.removeDeparted(profileID)
+	if !l.hasRoomFor(*p) {
+		l.addToWaitlist(*p)
+		return nil
+	}
+
+	if p.Role == profile.Mentor {
+		l.Mentors = append(l.Mentors, *p)
+	} else {
+		l.Players = append(l.Players, *p)
+	}
+
+	l.emit(ProfileRejoined, ResponseFromProfile(p))
+	return nil
+}
+
+func (l *Lobby) isWaitlisted(profileID string) bool {
+	for _, p := range l.Waitlist {
+		if p.ID == profileID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Lobby) isKicked(profileID string) bool {
+	for _, id := range l.Kicked {
+		if id == profileID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeMember removes a player or mentor and returns it, or nil when the
+// profile is neither.
+func (l *Lobby) removeMember(profileID string) *profile.Profile {
+	if p := l.getPlayer(profileID); p != nil {
+		removed := *p
+		l.removePlayer(profileID)
+		return &removed
+	}
+
+	for i, mentor := range l.Mentors {
+		if mentor.ID == profileID {
+			l.Mentors = append(l.Mentors[:i], l.Mentors[i+1:]...)
+			return &mentor
+		}
+	}
+
+	return nil
+}
+
+func (l *Lobby) removeDeparted(profileID string) *profile.Profile {
+	for i, p := range l.Departed {
+		if p.ID == profileID {
+			l.Departed = append(l.Departed[:i], l.Departed[i+1:]...)
+			return &p
+		}
+	}
+
+	return nil
+}
+
+func member(l *Lobby, actor profile.Profile) error {
+	if l.getPlayer(actor.ID) == nil && l.getMentor(actor.ID) == nil && !l.isWaitlisted(actor.ID) {
+		return ErrProfileNotInLobby
+	}
+
+	return nil
+}
+
+func notKicked(l *Lobby, actor profile.Profile) error {
+	if l.isKicked(actor.ID) {
+		return ErrProfileKicked
+	}
+
+	return nil
+}
+
+func departed(l *Lobby, actor profile.Profile) error {
+	for _, p := range l.Departed {
+		if p.ID == actor.ID {
+			return nil
+		}
+	}
+
+	return ErrProfileNotInLobby
+}
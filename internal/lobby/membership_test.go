@@ -0,0 +1,138 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestLeaveAndRejoin(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	playerProfile := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	mentorProfile := profile.NewMentor("Mentor", "avatar")
+
+	_ = lobby.Join(playerProfile)
+	_ = lobby.Join(mentorProfile)
+
+	joinTimestamp := lobby.Players[0].JoinTimestamp - 60
+	lobby.Players[0].JoinTimestamp = joinTimestamp // joined a minute ago
+
+	err := lobby.Leave(playerProfile.ID)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Players) != 0 {
+		t.Errorf("Expected Players to be empty, got %+v", lobby.Players)
+	}
+
+	err = lobby.Leave(playerProfile.ID)
+
+	if !errors.Is(err, ErrProfileNotInLobby) {
+		t.Errorf("Expected ErrProfileNotInLobby, got %v", err)
+	}
+
+	err = lobby.Rejoin(playerProfile.ID)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Players) != 1 || lobby.Players[0].JoinTimestamp != joinTimestamp {
+		t.Errorf("Expected the player back with JoinTimestamp %d, got %+v", joinTimestamp, lobby.Players)
+	}
+
+	err = lobby.Rejoin(playerProfile.ID)
+
+	if !errors.Is(err, ErrProfileNotInLobby) {
+		t.Errorf("Expected ErrProfileNotInLobby when rejoining twice, got %v", err)
+	}
+
+	_ = lobby.Leave(mentorProfile.ID)
+	_ = lobby.Rejoin(mentorProfile.ID)
+
+	if len(lobby.Mentors) != 1 || len(lobby.Players) != 1 {
+		t.Errorf("Expected the mentor back in Mentors, got %+v and %+v", lobby.Mentors, lobby.Players)
+	}
+}
+
+func TestLeave_WhenNotWaiting(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 2)
+
+	err := lobby.Leave(lobby.Players[0].ID)
+
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestKick(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	playerProfile := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	_ = lobby.Join(playerProfile)
+
+	err := lobby.Kick(playerProfile.ID, playerProfile.ID)
+
+	if !errors.Is(err, ErrNotMaster) {
+		t.Errorf("Expected ErrNotMaster, got %v", err)
+	}
+
+	err = lobby.Kick(masterProfile.ID, masterProfile.ID)
+
+	if !errors.Is(err, ErrProfileNotInLobby) {
+		t.Errorf("Expected ErrProfileNotInLobby, got %v", err)
+	}
+
+	err = lobby.Kick(masterProfile.ID, playerProfile.ID)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Players) != 0 {
+		t.Errorf("Expected Players to be empty, got %+v", lobby.Players)
+	}
+
+	err = lobby.Join(playerProfile)
+
+	if !errors.Is(err, ErrProfileKicked) {
+		t.Errorf("Expected ErrProfileKicked on join, got %v", err)
+	}
+
+	err = lobby.Rejoin(playerProfile.ID)
+
+	if !errors.Is(err, ErrProfileKicked) {
+		t.Errorf("Expected ErrProfileKicked on rejoin, got %v", err)
+	}
+}
+
+func TestKick_WhenProfileLeft(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	playerProfile := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	_ = lobby.Join(playerProfile)
+	_ = lobby.Leave(playerProfile.ID)
+
+	err := lobby.Kick(masterProfile.ID, playerProfile.ID)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(lobby.Departed) != 0 {
+		t.Errorf("Expected Departed to be empty, got %+v", lobby.Departed)
+	}
+
+	err = lobby.Rejoin(playerProfile.ID)
+
+	if !errors.Is(err, ErrProfileKicked) {
+		t.Errorf("Expected ErrProfileKicked, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package lobby
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// PriorityStrategyName identifies a PriorityStrategy when it is stored or
+// sent over the API.
+type PriorityStrategyName string
+
+const (
+	DefaultPriorityStrategy  PriorityStrategyName = "default"  // Leadership and GDP first, then elected leaders
+	WeightedPriorityStrategy PriorityStrategyName = "weighted" // the leaders whose skills weigh more choose first
+	LotteryPriorityStrategy  PriorityStrategyName = "lottery"  // the leaders are drawn in an order decided by the seed
+)
+
+// PriorityStrategy decides in which order the leaders choose their teams.
+type PriorityStrategy interface {
+	// Rank returns the selection priority of every player, in the order of
+	// players, which are sorted by JoinTimestamp. Lower priorities choose
+	// first and -1 leaves the priority of the player unchanged.
+	Rank(players []profile.Profile) []int
+	Settings() PrioritySettings
+}
+
+// PrioritySettings describes a PriorityStrategy, so it can be stored and
+// built again with Strategy.
+type PrioritySettings struct {
+	Name             PriorityStrategyName      `json:"name"`
+	HardSkillWeights map[profile.HardSkill]int `json:"hard_skill_weights,omitempty"`
+	SoftSkillWeights map[profile.SoftSkill]int `json:"soft_skill_weights,omitempty"`
+	Seed             int64                     `json:"seed,omitempty"`
+}
+
+// Strategy builds the strategy described by the settings. An empty name is
+// the default strategy.
+func (s PrioritySettings) Strategy() (PriorityStrategy, error) {
+	switch s.Name {
+	case "", DefaultPriorityStrategy:
+		return DefaultPriority{}, nil
+	case WeightedPriorityStrategy:
+		return WeightedPriority{HardSkills: s.HardSkillWeights, SoftSkills: s.SoftSkillWeights}, nil
+	case LotteryPriorityStrategy:
+		return LotteryPriority{Seed: s.Seed}, nil
+	}
+
+	return nil, ErrInvalidPriorityStrategy.WithDetails(map[string]interface{}{"priority_strategy": s.Name})
+}
+
+// WithPriorityStrategy sets how the leaders are ranked for the draft.
+func WithPriorityStrategy(strategy PriorityStrategy) LobbyOption {
+	return func(l *Lobby) {
+		l.PriorityStrategy = strategy
+	}
+}
+
+const (
+	LeadershipWeight    = 10
+	GDPWeight           = 100
+	ElectedLeaderWeight = 1000
+)
+
+// DefaultPriority ranks the leaders with Leadership and GDP first, then
+// Leadership, then GDP, then the others, such as the elected ones, by order
+// of arrival within each group.
+type DefaultPriority struct{}
+
+func (DefaultPriority) Rank(players []profile.Profile) []int {
+	priorities := make([]int, len(players))
+
+	currentPriority := 0
+	for i, p := range players {
+		priorities[i] = calculatePriorityWeight(p, currentPriority)
+
+		if priorities[i] >= 0 {
+			currentPriority++
+		}
+	}
+
+	return priorities
+}
+
+func (DefaultPriority) Settings() PrioritySettings {
+	return PrioritySettings{Name: DefaultPriorityStrategy}
+}
+
+// less than 0 means that the player is not eligible to select
+// less is more eligible
+func calculatePriorityWeight(p profile.Profile, currentPriority int) int {
+	switch {
+	case p.Role != profile.Leader:
+		return -1
+	case p.HasSoftSkill(profile.Leadership) && p.HasHardSkill(profile.GDP):
+		return currentPriority
+	case p.HasSoftSkill(profile.Leadership):
+		return currentPriority + LeadershipWeight
+	case p.HasHardSkill(profile.GDP):
+		return currentPriority + GDPWeight
+	case p.Role == profile.Leader:
+		return currentPriority + ElectedLeaderWeight
+	}
+
+	return -1
+}
+
+// WeightedPriority ranks the leaders by the sum of the weights of their
+// skills, the highest first. Skills without a weight count as zero and ties
+// go to the first to arrive.
+type WeightedPriority struct {
+	HardSkills map[profile.HardSkill]int
+	SoftSkills map[profile.SoftSkill]int
+}
+
+func (w WeightedPriority) Rank(players []profile.Profile) []int {
+	scores := make(map[int]int)
+	for i, p := range players {
+		if !leaderCandidate(p) {
+			continue
+		}
+
+		for _, skill := range p.HardSkills {
+			scores[i] += w.HardSkills[skill]
+		}
+
+		for _, skill := range p.SoftSkills {
+			scores[i] += w.SoftSkills[skill]
+		}
+	}
+
+	return rankCandidates(players, func(candidates []int) {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return scores[candidates[i]] > scores[candidates[j]]
+		})
+	})
+}
+
+func (w WeightedPriority) Settings() PrioritySettings {
+	return PrioritySettings{Name: WeightedPriorityStrategy, HardSkillWeights: w.HardSkills, SoftSkillWeights: w.SoftSkills}
+}
+
+// LotteryPriority draws the order of the leaders. The same seed over the
+// same players always draws the same order.
+type LotteryPriority struct {
+	Seed int64
+}
+
+func (lottery LotteryPriority) Rank(players []profile.Profile) []int {
+	random := rand.New(rand.NewSource(lottery.Seed))
+
+	return rankCandidates(players, func(candidates []int) {
+		random.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	})
+}
+
+func (lottery LotteryPriority) Settings() PrioritySettings {
+	return PrioritySettings{Name: LotteryPriorityStrategy, Seed: lottery.Seed}
+}
+
+// rankCandidates gives the leader candidates, in the order left by sortFn,
+// the priorities 0, 1, 2... and -1 to everybody else.
+func rankCandidates(players []profile.Profile, sortFn func(candidates []int)) []int {
+	priorities := make([]int, len(players))
+	candidates := make([]int, 0, len(players))
+
+	for i, p := range players {
+		priorities[i] = -1
+		if leaderCandidate(p) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	sortFn(candidates)
+	for priority, i := range candidates {
+		priorities[i] = priority
+	}
+
+	return priorities
+}
+
+// leaderCandidate reports whether the player takes part in the ranking. Only
+// leaders choose teams.
+func leaderCandidate(p profile.Profile) bool {
+	return p.Role == profile.Leader
+}
+
+func (l *Lobby) priorityStrategy() PriorityStrategy {
+	if l.PriorityStrategy == nil { // lobbies created before priority strategies existed
+		return DefaultPriority{}
+	}
+
+	return l.PriorityStrategy
+}
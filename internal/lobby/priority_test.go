@@ -0,0 +1,125 @@
+package lobby
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// newRankedPlayers returns, in order of arrival, a player without
+// leadership, an elected leader, a GDP leader and a Leadership leader.
+func newRankedPlayers() []profile.Profile {
+	player := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	elected := profile.NewPlayer("Elected", "avatar", []profile.HardSkill{profile.Design}, []profile.SoftSkill{profile.Creativity})
+	elected.Role = profile.Leader
+	gdp := profile.NewPlayer("GDP", "avatar", []profile.HardSkill{profile.GDP}, []profile.SoftSkill{profile.Empathy})
+	leadership := profile.NewPlayer("Leadership", "avatar", []profile.HardSkill{profile.Programming}, []profile.SoftSkill{profile.Leadership})
+
+	gdp.Role = profile.Leader
+	leadership.Role = profile.Leader
+
+	players := []profile.Profile{player, elected, gdp, leadership}
+	for i := range players {
+		players[i].JoinTimestamp = int64(i)
+	}
+
+	return players
+}
+
+func TestDefaultPriority_Rank(t *testing.T) {
+	priorities := DefaultPriority{}.Rank(newRankedPlayers())
+
+	expected := []int{-1, ElectedLeaderWeight, 1 + GDPWeight, 2 + LeadershipWeight}
+	if !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("Expected priorities %v, got %v", expected, priorities)
+	}
+}
+
+func TestWeightedPriority_Rank(t *testing.T) {
+	strategy := WeightedPriority{
+		HardSkills: map[profile.HardSkill]int{profile.Design: 5, profile.GDP: 1},
+		SoftSkills: map[profile.SoftSkill]int{profile.Empathy: 1},
+	}
+
+	priorities := strategy.Rank(newRankedPlayers())
+
+	// Elected weighs 5, GDP 2 and Leadership 0
+	expected := []int{-1, 0, 1, 2}
+	if !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("Expected priorities %v, got %v", expected, priorities)
+	}
+}
+
+func TestWeightedPriority_TiesGoToTheFirstToArrive(t *testing.T) {
+	priorities := WeightedPriority{}.Rank(newRankedPlayers())
+
+	expected := []int{-1, 0, 1, 2}
+	if !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("Expected priorities %v, got %v", expected, priorities)
+	}
+}
+
+func TestLotteryPriority_Rank(t *testing.T) {
+	players := newRankedPlayers()
+
+	first := LotteryPriority{Seed: 7}.Rank(players)
+	second := LotteryPriority{Seed: 7}.Rank(players)
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same seed to draw the same order, got %v and %v", first, second)
+	}
+
+	if first[0] != -1 {
+		t.Errorf("Expected the player without leadership out of the lottery, got %d", first[0])
+	}
+
+	drawn := map[int]bool{}
+	for _, priority := range first[1:] {
+		drawn[priority] = true
+	}
+
+	if len(drawn) != 3 || !drawn[0] || !drawn[1] || !drawn[2] {
+		t.Errorf("Expected the leaders to get the priorities 0, 1 and 2, got %v", first)
+	}
+}
+
+func TestPrioritySettings_Strategy(t *testing.T) {
+	cases := []PriorityStrategy{
+		DefaultPriority{},
+		WeightedPriority{HardSkills: map[profile.HardSkill]int{profile.GDP: 3}},
+		LotteryPriority{Seed: 42},
+	}
+
+	for _, strategy := range cases {
+		rebuilt, err := strategy.Settings().Strategy()
+		if err != nil || !reflect.DeepEqual(rebuilt, strategy) {
+			t.Errorf("Expected %+v to be rebuilt from its settings, got %+v (%v)", strategy, rebuilt, err)
+		}
+	}
+
+	if strategy, err := (PrioritySettings{}).Strategy(); err != nil || strategy != (DefaultPriority{}) {
+		t.Errorf("Expected the default strategy for empty settings, got %+v (%v)", strategy, err)
+	}
+
+	if _, err := (PrioritySettings{Name: "alphabetical"}).Strategy(); !errors.Is(err, ErrInvalidPriorityStrategy) {
+		t.Errorf("Expected ErrInvalidPriorityStrategy, got %v", err)
+	}
+}
+
+func TestDefinePriorities_WithStrategy(t *testing.T) {
+	strategy := WeightedPriority{HardSkills: map[profile.HardSkill]int{profile.Design: 5}}
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Priority Lobby", 2, 2, WithPriorityStrategy(strategy))
+	lobby.Players = newRankedPlayers()
+
+	lobby.DefinePriorities()
+
+	if leader := lobby.getPlayer(lobby.Players[1].ID); leader.Name != "Elected" || leader.SelectionPriority != 0 {
+		t.Errorf("Expected Elected to choose first, got %+v", leader)
+	}
+
+	if response := ResponseFromLobby(lobby); response.PriorityStrategy.Name != WeightedPriorityStrategy {
+		t.Errorf("Expected the response to show the weighted strategy, got %v", response.PriorityStrategy.Name)
+	}
+}
@@ -0,0 +1,17 @@
+package lobby
+
+import (
+	"context"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// ProfileRepository stores the identities participants keep across lobbies.
+type ProfileRepository interface {
+	Save(ctx context.Context, identity profile.Identity) error
+	// FindByID returns ErrProfileNotFound when there is no identity with the id.
+	FindByID(ctx context.Context, id string) (profile.Identity, error)
+	// Update replaces a stored identity, returning ErrProfileNotFound when
+	// there is none with its id.
+	Update(ctx context.Context, identity profile.Identity) error
+}
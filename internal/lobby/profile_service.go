@@ -0,0 +1,193 @@
+package lobby
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// ProfileService manages the identities participants keep across lobbies.
+// Joining a lobby with one copies it into the lobby, so later changes only
+// reach the lobbies joined afterwards. Using or changing an identity takes
+// the secret returned when it was created.
+type ProfileService struct {
+	repo ProfileRepository
+}
+
+func NewProfileService(repo ProfileRepository) *ProfileService {
+	return &ProfileService{repo: repo}
+}
+
+// ProfilePatch holds the changes to an identity. Nil fields are left as they
+// are, and the levels are merged into the current ones.
+type ProfilePatch struct {
+	Name            *string
+	Avatar          *string
+	HardSkills      *[]profile.HardSkill // the levels of the skills removed are dropped
+	SoftSkills      *[]profile.SoftSkill
+	HardSkillLevels map[profile.HardSkill]profile.Proficiency
+	SoftSkillLevels map[profile.SoftSkill]profile.Proficiency
+}
+
+func (service *ProfileService) CreateProfile(ctx context.Context, identity profile.Identity) (*CreatedIdentityResponse, error) {
+	identity, err := validIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newProfileSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	identity.SecretHash = hashProfileSecret(secret)
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = identity.CreatedAt
+
+	if err := service.repo.Save(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return &CreatedIdentityResponse{IdentityResponse: ResponseFromIdentity(&identity), Secret: secret}, nil
+}
+
+func (service *ProfileService) GetProfile(ctx context.Context, id string) (*IdentityResponse, error) {
+	identity, err := service.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResponseFromIdentity(&identity), nil
+}
+
+// AuthenticateIdentity returns the identity to join a lobby with, when the
+// secret is the one returned when it was created.
+func (service *ProfileService) AuthenticateIdentity(ctx context.Context, id string, secret string) (profile.Identity, error) {
+	if secret == "" {
+		return profile.Identity{}, ErrProfileSecretRequired
+	}
+
+	identity, err := service.repo.FindByID(ctx, id)
+	if err != nil {
+		return profile.Identity{}, err
+	}
+
+	// identities stored before secrets existed have no hash and match none
+	expected, err := hex.DecodeString(identity.SecretHash)
+	if err != nil || len(expected) == 0 || subtle.ConstantTimeCompare(expected, profileSecretSum(secret)) != 1 {
+		return profile.Identity{}, ErrWrongProfileSecret
+	}
+
+	return identity, nil
+}
+
+// UpdateProfile applies the patch to the identity the secret authenticates.
+// The last update wins.
+func (service *ProfileService) UpdateProfile(ctx context.Context, id string, secret string, patch ProfilePatch) (*IdentityResponse, error) {
+	identity, err := service.AuthenticateIdentity(ctx, id, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err = validIdentity(patch.apply(identity))
+	if err != nil {
+		return nil, err
+	}
+
+	identity.UpdatedAt = time.Now().Unix()
+
+	if err := service.repo.Update(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return ResponseFromIdentity(&identity), nil
+}
+
+func (patch ProfilePatch) apply(identity profile.Identity) profile.Identity {
+	if patch.Name != nil {
+		identity.Name = *patch.Name
+	}
+
+	if patch.Avatar != nil {
+		identity.Avatar = *patch.Avatar
+	}
+
+	p := identity.Player()
+
+	if patch.HardSkills != nil {
+		p.HardSkills = *patch.HardSkills
+		for skill := range p.HardSkillLevels {
+			if !p.HasHardSkill(skill) {
+				delete(p.HardSkillLevels, skill)
+			}
+		}
+	}
+
+	if patch.SoftSkills != nil {
+		p.SoftSkills = *patch.SoftSkills
+		for skill := range p.SoftSkillLevels {
+			if !p.HasSoftSkill(skill) {
+				delete(p.SoftSkillLevels, skill)
+			}
+		}
+	}
+
+	for skill, proficiency := range patch.HardSkillLevels {
+		p.SetHardSkillLevel(skill, proficiency)
+	}
+
+	for skill, proficiency := range patch.SoftSkillLevels {
+		p.SetSoftSkillLevel(skill, proficiency)
+	}
+
+	patched := p.Identity()
+	patched.CreatedAt = identity.CreatedAt
+	patched.UpdatedAt = identity.UpdatedAt
+	patched.SecretHash = identity.SecretHash
+	return patched
+}
+
+// newProfileSecret returns 32 random bytes, base64url encoded. They are hard
+// enough to guess that a plain hash is enough to store them.
+func newProfileSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func hashProfileSecret(secret string) string {
+	return hex.EncodeToString(profileSecretSum(secret))
+}
+
+func profileSecretSum(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// validIdentity checks the identity and gives DefaultSkillLevel to the skills
+// without a level. Skills are checked against the catalogue of each lobby
+// when the identity joins it.
+func validIdentity(identity profile.Identity) (profile.Identity, error) {
+	if identity.Name == "" {
+		return identity, ErrInvalidProfile.WithDetails(map[string]interface{}{"reason": "the profile has no name"})
+	}
+
+	p := identity.Player()
+	if err := checkSkillLevels(p); err != nil {
+		return identity, err
+	}
+
+	p.FillSkillLevels()
+	identity.HardSkillLevels = p.HardSkillLevels
+	identity.SoftSkillLevels = p.SoftSkillLevels
+	return identity, nil
+}
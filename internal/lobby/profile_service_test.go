@@ -0,0 +1,200 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// ProfileRepositoryMock keeps the identities in a map, as they were saved.
+type ProfileRepositoryMock struct {
+	identities map[string]profile.Identity
+}
+
+func NewProfileRepositoryMock() *ProfileRepositoryMock {
+	return &ProfileRepositoryMock{identities: make(map[string]profile.Identity)}
+}
+
+func (r *ProfileRepositoryMock) Save(ctx context.Context, identity profile.Identity) error {
+	r.identities[identity.ID] = identity
+	return nil
+}
+
+func (r *ProfileRepositoryMock) FindByID(ctx context.Context, id string) (profile.Identity, error) {
+	identity, ok := r.identities[id]
+	if !ok {
+		return profile.Identity{}, ErrProfileNotFound
+	}
+
+	return identity, nil
+}
+
+func (r *ProfileRepositoryMock) Update(ctx context.Context, identity profile.Identity) error {
+	if _, ok := r.identities[identity.ID]; !ok {
+		return ErrProfileNotFound
+	}
+
+	r.identities[identity.ID] = identity
+	return nil
+}
+
+func newIdentity(name string) profile.Identity {
+	p := profile.NewPlayer(name, "avatar", []profile.HardSkill{profile.Programming, profile.Design}, []profile.SoftSkill{profile.Empathy})
+	return p.Identity()
+}
+
+func TestCreateProfile(t *testing.T) {
+	service := NewProfileService(NewProfileRepositoryMock())
+
+	created, err := service.CreateProfile(context.Background(), newIdentity("Ana"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created.CreatedAt == 0 || created.UpdatedAt != created.CreatedAt {
+		t.Errorf("Expected the timestamps to be set, got %d and %d", created.CreatedAt, created.UpdatedAt)
+	}
+
+	found, err := service.GetProfile(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("Expected the profile to be found, got %v", err)
+	}
+
+	if found.Name != "Ana" || found.HardSkillLevels[profile.Design].Level != profile.DefaultSkillLevel {
+		t.Errorf("Expected the stored profile, got %+v", found)
+	}
+}
+
+func TestCreateProfile_Invalid(t *testing.T) {
+	service := NewProfileService(NewProfileRepositoryMock())
+
+	if _, err := service.CreateProfile(context.Background(), newIdentity("")); !errors.Is(err, ErrInvalidProfile) {
+		t.Errorf("Expected ErrInvalidProfile, got %v", err)
+	}
+
+	identity := newIdentity("Ana")
+	identity.SoftSkillLevels[profile.Empathy] = profile.Proficiency{Level: 0}
+
+	if _, err := service.CreateProfile(context.Background(), identity); !errors.Is(err, ErrInvalidSkillLevel) {
+		t.Errorf("Expected ErrInvalidSkillLevel, got %v", err)
+	}
+}
+
+func TestGetProfile_NotFound(t *testing.T) {
+	service := NewProfileService(NewProfileRepositoryMock())
+
+	if _, err := service.GetProfile(context.Background(), "missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+
+	if _, err := service.UpdateProfile(context.Background(), "missing", "secret", ProfilePatch{}); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	service := NewProfileService(NewProfileRepositoryMock())
+	identity := newIdentity("Ana")
+	identity.HardSkillLevels[profile.Programming] = profile.Proficiency{Level: 4}
+	created, _ := service.CreateProfile(context.Background(), identity)
+
+	avatar := "new avatar"
+	hardSkills := []profile.HardSkill{profile.Programming, profile.IA}
+	updated, err := service.UpdateProfile(context.Background(), created.ID, created.Secret, ProfilePatch{
+		Avatar:          &avatar,
+		HardSkills:      &hardSkills,
+		SoftSkillLevels: map[profile.SoftSkill]profile.Proficiency{profile.Empathy: {Level: 5, Notes: "mentors juniors"}},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated.Name != "Ana" || updated.Avatar != avatar {
+		t.Errorf("Expected only the avatar to change, got %s and %s", updated.Name, updated.Avatar)
+	}
+
+	if _, ok := updated.HardSkillLevels[profile.Design]; ok {
+		t.Errorf("Expected the level of the removed skill to be dropped, got %+v", updated.HardSkillLevels)
+	}
+
+	if updated.HardSkillLevels[profile.Programming].Level != 4 || updated.HardSkillLevels[profile.IA].Level != profile.DefaultSkillLevel {
+		t.Errorf("Expected the kept level and a default level for the new skill, got %+v", updated.HardSkillLevels)
+	}
+
+	if updated.SoftSkillLevels[profile.Empathy].Notes != "mentors juniors" {
+		t.Errorf("Expected the soft skill level to be merged, got %+v", updated.SoftSkillLevels)
+	}
+
+	if updated.CreatedAt != created.CreatedAt {
+		t.Errorf("Expected CreatedAt to be kept, got %d", updated.CreatedAt)
+	}
+
+	if _, err := service.AuthenticateIdentity(context.Background(), created.ID, created.Secret); err != nil {
+		t.Errorf("Expected the secret to still authenticate the profile, got %v", err)
+	}
+}
+
+func TestAuthenticateIdentity(t *testing.T) {
+	repo := NewProfileRepositoryMock()
+	service := NewProfileService(repo)
+	created, _ := service.CreateProfile(context.Background(), newIdentity("Ana"))
+
+	if created.Secret == "" {
+		t.Fatalf("Expected a secret to be returned")
+	}
+
+	identity, err := service.AuthenticateIdentity(context.Background(), created.ID, created.Secret)
+	if err != nil || identity.ID != created.ID {
+		t.Errorf("Expected the profile, got %+v and %v", identity, err)
+	}
+
+	if _, err := service.AuthenticateIdentity(context.Background(), created.ID, ""); !errors.Is(err, ErrProfileSecretRequired) {
+		t.Errorf("Expected ErrProfileSecretRequired, got %v", err)
+	}
+
+	if _, err := service.AuthenticateIdentity(context.Background(), created.ID, "guessed"); !errors.Is(err, ErrWrongProfileSecret) {
+		t.Errorf("Expected ErrWrongProfileSecret, got %v", err)
+	}
+
+	name := "Someone else"
+	if _, err := service.UpdateProfile(context.Background(), created.ID, "guessed", ProfilePatch{Name: &name}); !errors.Is(err, ErrWrongProfileSecret) {
+		t.Errorf("Expected ErrWrongProfileSecret, got %v", err)
+	}
+
+	if found, _ := service.GetProfile(context.Background(), created.ID); found.Name != "Ana" {
+		t.Errorf("Expected the name to be kept, got %s", found.Name)
+	}
+
+	// stored before profiles had secrets
+	legacy := newIdentity("Bia")
+	_ = repo.Save(context.Background(), legacy)
+
+	if _, err := service.AuthenticateIdentity(context.Background(), legacy.ID, "any"); !errors.Is(err, ErrWrongProfileSecret) {
+		t.Errorf("Expected ErrWrongProfileSecret, got %v", err)
+	}
+}
+
+func TestJoin_SameIdentityInManyLobbies(t *testing.T) {
+	identity := newIdentity("Ana")
+	first := NewLobby(profile.NewMaster("Master", "avatar"), "First", 2, 2)
+	second := NewLobby(profile.NewMaster("Master", "avatar"), "Second", 2, 2)
+
+	if err := first.Join(identity.Player()); err != nil {
+		t.Fatalf("Expected to join the first lobby, got %v", err)
+	}
+
+	if err := second.Join(identity.Player()); err != nil {
+		t.Fatalf("Expected to join the second lobby, got %v", err)
+	}
+
+	if first.Players[0].ID != identity.ID || second.Players[0].ID != identity.ID {
+		t.Errorf("Expected both lobbies to know the profile as %s", identity.ID)
+	}
+
+	if err := first.Join(identity.Player()); !errors.Is(err, ErrProfileAlreadyInLobby) {
+		t.Errorf("Expected ErrProfileAlreadyInLobby, got %v", err)
+	}
+}
@@ -0,0 +1,369 @@
+package lobby
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// Replay rebuilds a lobby from its events, in the order they were recorded.
+// The first event must be LobbyCreated. Events only describe changes, so
+// replaying them never checks the rules of the lobby again.
+func Replay(events []LobbyEvent) (*Lobby, error) {
+	if len(events) == 0 || events[0].Type != LobbyCreated {
+		return nil, ErrInvalidEventLog.WithDetails(map[string]interface{}{"reason": "the first event must be lobby_created"})
+	}
+
+	l := &Lobby{}
+	for i, event := range events {
+		if err := l.apply(event); err != nil {
+			return nil, ErrInvalidEventLog.WithDetails(map[string]interface{}{
+				"index":  i,
+				"type":   event.Type,
+				"reason": err.Error(),
+			})
+		}
+
+		l.Version = event.Version
+	}
+
+	return l, nil
+}
+
+// apply changes the lobby the same way the method that recorded the event
+// did.
+func (l *Lobby) apply(event LobbyEvent) error {
+	switch event.Type {
+	case LobbyCreated:
+		payload, err := payloadOf[LobbyCreatedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		strategy, err := payload.PriorityStrategy.Strategy()
+		if err != nil {
+			return err
+		}
+
+		*l = Lobby{
+			ID:            payload.ID,
+			AccessCode:    event.AccessCode,
+			Master:        payload.Master.toProfile(),
+			Name:          payload.Name,
+			MaxHardSkills: payload.MaxHardSkills,
+			MaxSoftSkills: payload.MaxSoftSkills,
+			Capacity: Capacity{
+				MaxPlayers:        payload.MaxPlayers,
+				MaxMentors:        payload.MaxMentors,
+				MinPlayersPerTeam: payload.MinPlayersPerTeam,
+				MaxPlayersPerTeam: payload.MaxPlayersPerTeam,
+			},
+			Players:          []profile.Profile{},
+			Mentors:          []profile.Profile{},
+			Status:           Waiting,
+			StatusTimestamps: map[LobbyStatus]int64{Waiting: event.Timestamp},
+			CreatedAt:        event.Timestamp,
+			TurnTimeout:      payload.TurnTimeout,
+			DraftOrder:       payload.DraftOrder,
+			TeamFormation:    payload.TeamFormation,
+			PriorityStrategy: strategy,
+			LeaderRule:       payload.LeaderRule,
+			SkillCatalogue:   payload.SkillCatalogue,
+		}
+	case PlayerJoined, MentorJoined, ProfileRejoined, WaitlistPromoted:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		l.removeDeparted(payload.ID)
+		l.removeFromWaitlist(payload.ID)
+		l.addMember(payload.toProfile())
+	case ProfileWaitlisted:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		l.removeDeparted(payload.ID) // a rejoin waits for a spot as well
+		l.Waitlist = append(l.Waitlist, payload.toProfile())
+	case WaitlistDismissed:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		if l.removeFromWaitlist(payload.ID) == nil {
+			return ErrProfileNotInLobby
+		}
+	case ProfileLeft:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		if l.removeFromWaitlist(payload.ID) != nil {
+			return nil
+		}
+
+		p := l.removeMember(payload.ID)
+		if p == nil {
+			return ErrProfileNotInLobby
+		}
+
+		l.Departed = append(l.Departed, *p)
+	case ProfileKicked:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		if l.removeMember(payload.ID) == nil && l.removeDeparted(payload.ID) == nil {
+			l.removeFromWaitlist(payload.ID)
+		}
+
+		l.Kicked = append(l.Kicked, payload.ID)
+	case StatusChanged:
+		payload, err := payloadOf[StatusChangedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		// every status change ends the current turn, a new one is recorded
+		// as TurnChanged right after
+		l.Status = payload.To
+		l.ChooseControl = nil
+		if l.StatusTimestamps == nil {
+			l.StatusTimestamps = make(map[LobbyStatus]int64)
+		}
+		l.StatusTimestamps[payload.To] = event.Timestamp
+	case TurnChanged:
+		payload, err := payloadOf[TurnChangedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		l.ChooseControl = &ChooseControl{
+			ChoosingNow: payload.ChoosingNow.toProfile(),
+			Type:        payload.Type,
+			Deadline:    payload.Deadline,
+		}
+	case TeamsBuilt:
+		for i, mentor := range l.Mentors {
+			team := NewTeam(i, mentor)
+			l.Teams = append(l.Teams, &team)
+		}
+	case LeaderPromoted:
+		payload, err := payloadOf[ProfileResponse](event)
+		if err != nil {
+			return err
+		}
+
+		player := l.getPlayer(payload.ID)
+		if player == nil {
+			return ErrProfileNotInLobby
+		}
+
+		player.Role = profile.Leader
+	case PrioritiesDefined:
+		payload, err := payloadOf[PrioritiesDefinedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		sort.SliceStable(l.Players, func(i, j int) bool {
+			return l.Players[i].JoinTimestamp < l.Players[j].JoinTimestamp
+		})
+
+		for i, p := range l.Players {
+			if priority, ok := payload.Priorities[p.ID]; ok {
+				l.Players[i].SelectionPriority = priority
+			}
+		}
+
+		sortByPriority(l.Players) // as GetNextLeader does right after
+	case TeamSelected:
+		payload, err := payloadOf[TeamSelectedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		team := l.getTeam(payload.TeamID)
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		leader := payload.Leader.toProfile()
+		team.Leader = leader
+
+		if l.Status == LeaderTeamSelect { // BalanceTeams does not record a draft
+			l.recordDraftAction(DraftAction{Type: SelectTeam, TeamID: team.ID, Leader: leader, Timestamp: event.Timestamp})
+			sortByPriority(l.Players)
+		}
+
+		l.removePlayer(leader.ID)
+	case PlayerSelected:
+		payload, err := payloadOf[PlayerSelectedPayload](event)
+		if err != nil {
+			return err
+		}
+
+		team := l.getTeam(payload.TeamID)
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		player := payload.Player.toProfile()
+		team.Players = append(team.Players, player)
+
+		if l.Status == PlayerSelect {
+			l.recordDraftAction(DraftAction{Type: SelectPlayer, TeamID: team.ID, Leader: team.Leader, Player: player, Timestamp: event.Timestamp})
+		}
+
+		l.removePlayer(player.ID)
+	case SelectionUndone:
+		if _, err := l.revertDraftAction(); err != nil {
+			return err
+		}
+	case TurnExpired, TeamsBalanced, LobbyUpdated:
+		// the changes are recorded by the events that follow
+	default:
+		return ErrInvalidEventLog
+	}
+
+	return nil
+}
+
+func (l *Lobby) addMember(p profile.Profile) {
+	if p.Role == profile.Mentor {
+		l.Mentors = append(l.Mentors, p)
+		return
+	}
+
+	l.Players = append(l.Players, p)
+}
+
+func payloadOf[T any](event LobbyEvent) (T, error) {
+	payload, ok := event.Payload.(T)
+	if !ok {
+		return payload, ErrInvalidEventLog
+	}
+
+	return payload, nil
+}
+
+// DecodeEventPayload decodes a stored payload into the type the events of
+// eventType carry. decode fills the value it is given a pointer to, like
+// json.Unmarshal.
+func DecodeEventPayload(eventType LobbyEventType, decode func(v interface{}) error) (interface{}, error) {
+	switch eventType {
+	case LobbyCreated:
+		return decodePayload[LobbyCreatedPayload](decode)
+	case PlayerJoined, MentorJoined, ProfileLeft, ProfileKicked, ProfileRejoined, ProfileWaitlisted, WaitlistPromoted, WaitlistDismissed, LeaderPromoted:
+		return decodePayload[ProfileResponse](decode)
+	case StatusChanged:
+		return decodePayload[StatusChangedPayload](decode)
+	case TeamsBuilt:
+		return decodePayload[TeamsBuiltPayload](decode)
+	case PrioritiesDefined:
+		return decodePayload[PrioritiesDefinedPayload](decode)
+	case TeamSelected:
+		return decodePayload[TeamSelectedPayload](decode)
+	case PlayerSelected:
+		return decodePayload[PlayerSelectedPayload](decode)
+	case TurnChanged, TurnExpired:
+		return decodePayload[TurnChangedPayload](decode)
+	case SelectionUndone:
+		return decodePayload[SelectionUndonePayload](decode)
+	case TeamsBalanced:
+		return decodePayload[TeamsBalancedPayload](decode)
+	}
+
+	return nil, ErrInvalidEventLog.WithDetails(map[string]interface{}{"type": eventType})
+}
+
+func decodePayload[T any](decode func(v interface{}) error) (interface{}, error) {
+	var payload T
+	err := decode(&payload)
+	return payload, err
+}
+
+// SameState reports whether both lobbies hold the same state. Pending events
+// and the outbox are ignored, and so is whether empty lists are nil, which depends on where
+// the lobby was loaded from.
+func (l *Lobby) SameState(other *Lobby) bool {
+	return reflect.DeepEqual(l.normalized(), other.normalized())
+}
+
+func (l *Lobby) normalized() Lobby {
+	n := *l
+	n.events = nil
+	n.Outbox = nil
+	n.PriorityStrategy = l.priorityStrategy()
+	n.LeaderRule = l.leaderRule()
+	n.LeaderRule.HardSkills = append([]profile.HardSkill{}, n.LeaderRule.HardSkills...)
+	n.LeaderRule.SoftSkills = append([]profile.SoftSkill{}, n.LeaderRule.SoftSkills...)
+	n.SkillCatalogue = l.skillCatalogue()
+	n.SkillCatalogue.HardSkills = append([]SkillDefinition{}, n.SkillCatalogue.HardSkills...)
+	n.SkillCatalogue.SoftSkills = append([]SkillDefinition{}, n.SkillCatalogue.SoftSkills...)
+	n.Master = normalizedProfile(l.Master)
+	n.Players = normalizedProfiles(l.Players)
+	n.Mentors = normalizedProfiles(l.Mentors)
+	n.Departed = normalizedProfiles(l.Departed)
+	n.Waitlist = normalizedProfiles(l.Waitlist)
+	n.Kicked = append([]string{}, l.Kicked...)
+
+	n.StatusTimestamps = make(map[LobbyStatus]int64, len(l.StatusTimestamps))
+	for status, at := range l.StatusTimestamps {
+		n.StatusTimestamps[status] = at
+	}
+
+	n.Teams = make([]*Team, len(l.Teams))
+	for i, team := range l.Teams {
+		n.Teams[i] = &Team{
+			ID:      team.ID,
+			Mentor:  normalizedProfile(team.Mentor),
+			Leader:  normalizedProfile(team.Leader),
+			Players: normalizedProfiles(team.Players),
+		}
+	}
+
+	n.DraftHistory = make([]DraftAction, len(l.DraftHistory))
+	for i, action := range l.DraftHistory {
+		action.Leader = normalizedProfile(action.Leader)
+		action.Player = normalizedProfile(action.Player)
+		n.DraftHistory[i] = action
+	}
+
+	if l.ChooseControl != nil {
+		chooseControl := *l.ChooseControl
+		chooseControl.ChoosingNow = normalizedProfile(chooseControl.ChoosingNow)
+		n.ChooseControl = &chooseControl
+	}
+
+	return n
+}
+
+func normalizedProfiles(profiles []profile.Profile) []profile.Profile {
+	normalized := make([]profile.Profile, len(profiles))
+	for i, p := range profiles {
+		normalized[i] = normalizedProfile(p)
+	}
+
+	return normalized
+}
+
+func normalizedProfile(p profile.Profile) profile.Profile {
+	p.HardSkills = append([]profile.HardSkill{}, p.HardSkills...)
+	p.SoftSkills = append([]profile.SoftSkill{}, p.SoftSkills...)
+	if len(p.HardSkillLevels) == 0 {
+		p.HardSkillLevels = nil
+	}
+
+	if len(p.SoftSkillLevels) == 0 {
+		p.SoftSkillLevels = nil
+	}
+
+	return p
+}
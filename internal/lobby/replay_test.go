@@ -0,0 +1,223 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// eventLog collects the events of a lobby as the service would store them.
+type eventLog struct {
+	lobby  *Lobby
+	events []LobbyEvent
+}
+
+func (e *eventLog) record() {
+	e.events = append(e.events, e.lobby.PullEvents()...)
+}
+
+func (e *eventLog) checkReplay(t *testing.T) {
+	t.Helper()
+	e.record()
+
+	replayed, err := Replay(e.events)
+	if err != nil {
+		t.Fatalf("Expected the events to be replayed, got %v", err)
+	}
+
+	if !replayed.SameState(e.lobby) {
+		t.Errorf("Expected the replayed lobby to match the snapshot\nreplayed: %+v\nsnapshot: %+v", replayed, e.lobby)
+	}
+}
+
+func TestReplay_Draft(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Replay Lobby", 2, 2, WithTurnTimeout(time.Minute), WithDraftOrder(Snake))
+	log := &eventLog{lobby: lobby}
+
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewPlayer("Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership}))
+	for i := 0; i < 5; i++ {
+		_ = lobby.Join(newPlayer("Player"))
+	}
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	log.checkReplay(t)
+
+	if lobby.Status != LeaderElection {
+		t.Fatalf("Expected status %v, got %v", LeaderElection, lobby.Status)
+	}
+
+	_ = lobby.PromoteLeader(lobby.Players[len(lobby.Players)-1])
+	if err := lobby.StartLeaderTeamSelection(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	log.checkReplay(t)
+
+	_ = lobby.SelectTeam(lobby.ChooseControl.ChoosingNow, 0)
+	_ = lobby.UndoSelection()
+	_ = lobby.SelectTeam(lobby.ChooseControl.ChoosingNow, 0)
+	_ = lobby.AutoPick(time.Now().Add(time.Hour))
+	log.checkReplay(t)
+
+	if lobby.Status != PlayerSelect {
+		t.Fatalf("Expected status %v, got %v", PlayerSelect, lobby.Status)
+	}
+
+	_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	_ = lobby.UndoSelection()
+	_ = lobby.AutoPick(time.Now().Add(time.Hour))
+	log.checkReplay(t)
+
+	for lobby.Status == PlayerSelect {
+		_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	}
+	_ = lobby.UndoSelection()
+	_ = lobby.SelectPlayer(lobby.ChooseControl.ChoosingNow, lobby.Players[0].ID)
+	_ = lobby.Apply(StartAction)
+	log.checkReplay(t)
+
+	if lobby.Status != InProgress || len(lobby.DraftHistory) != 6 {
+		t.Errorf("Expected the draft to finish with 6 selections, got %v with %d", lobby.Status, len(lobby.DraftHistory))
+	}
+}
+
+func TestReplay_Membership(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Replay Lobby", 2, 2, WithCapacity(Capacity{MaxPlayers: 2, MaxMentors: 1}))
+	log := &eventLog{lobby: lobby}
+
+	first, second, third, fourth := newPlayer("First"), newPlayer("Second"), newPlayer("Third"), newPlayer("Fourth")
+	_ = lobby.Join(first)
+	_ = lobby.Join(second)
+	_ = lobby.Join(third)
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	log.checkReplay(t)
+
+	_ = lobby.Leave(first.ID)
+	_ = lobby.Join(fourth)
+	_ = lobby.Rejoin(first.ID)
+	_ = lobby.Kick(lobby.Master.ID, second.ID)
+	_ = lobby.Leave(lobby.Mentors[0].ID)
+	log.checkReplay(t)
+
+	if len(lobby.Waitlist) != 1 || len(lobby.Kicked) != 1 || len(lobby.Departed) != 1 {
+		t.Errorf("Expected one waitlisted, one kicked and one departed profile, got %+v", lobby)
+	}
+}
+
+func TestReplay_BalancedTeams(t *testing.T) {
+	lobby := newBalancedLobby()
+	log := &eventLog{lobby: lobby}
+
+	_ = lobby.BalanceTeams(42)
+	_ = lobby.Apply(StartAction)
+	_ = lobby.Apply(FinishAction)
+	log.checkReplay(t)
+}
+
+func TestReplay_WithoutLobbyCreated(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Replay Lobby", 2, 2)
+	_ = lobby.PullEvents()
+	_ = lobby.Join(newPlayer("Player"))
+
+	if _, err := Replay(lobby.PullEvents()); !errors.Is(err, ErrInvalidEventLog) {
+		t.Errorf("Expected ErrInvalidEventLog, got %v", err)
+	}
+
+	if _, err := Replay(nil); !errors.Is(err, ErrInvalidEventLog) {
+		t.Errorf("Expected ErrInvalidEventLog, got %v", err)
+	}
+}
+
+func TestEventLogService(t *testing.T) {
+	repo := NewLobbyRepositoryMock()
+	service := NewLobbyService(repo, NewLobbyEventStoreMock())
+	ctx := context.Background()
+
+	master := profile.NewMaster("Master", "avatar")
+	response, _ := service.CreateLobby(ctx, master, "Event Log Lobby", 2, 2)
+	player := newPlayer("Player")
+	_, _ = service.JoinLobby(ctx, response.AccessCode, player)
+
+	events, _ := service.Events(ctx, response.AccessCode)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	if events[0].Type != LobbyCreated || events[0].Actor != master.ID {
+		t.Errorf("Expected lobby_created by the master, got %v by %v", events[0].Type, events[0].Actor)
+	}
+
+	if events[1].Type != PlayerJoined || events[1].Actor != player.ID {
+		t.Errorf("Expected player_joined by the player, got %v by %v", events[1].Type, events[1].Actor)
+	}
+
+	if err := service.VerifyEventLog(ctx, response.AccessCode); err != nil {
+		t.Errorf("Expected the event log to match the lobby, got %v", err)
+	}
+
+	repo.Memory[response.AccessCode].Name = "Changed outside the domain"
+	if err := service.VerifyEventLog(ctx, response.AccessCode); !errors.Is(err, ErrEventLogMismatch) {
+		t.Errorf("Expected ErrEventLogMismatch, got %v", err)
+	}
+}
+
+// FailingEventStoreMock fails the first Failures appends, as an event store
+// that is down for a while.
+type FailingEventStoreMock struct {
+	*LobbyEventStoreMock
+	Failures int
+}
+
+func (s *FailingEventStoreMock) Append(ctx context.Context, events ...LobbyEvent) error {
+	if s.Failures > 0 {
+		s.Failures--
+		return errors.New("event store is down")
+	}
+
+	return s.LobbyEventStoreMock.Append(ctx, events...)
+}
+
+func TestEventLogService_DeliversOutboxLater(t *testing.T) {
+	backoff := deliveryBackoff
+	deliveryBackoff = time.Millisecond
+	defer func() { deliveryBackoff = backoff }()
+
+	repo := NewLobbyRepositoryMock()
+	// every attempt of the creation and of the delivery before the join fails
+	history := &FailingEventStoreMock{LobbyEventStoreMock: NewLobbyEventStoreMock(), Failures: 2 * deliveryAttempts}
+	service := NewLobbyService(repo, history)
+	ctx := context.Background()
+
+	response, err := service.CreateLobby(ctx, profile.NewMaster("Master", "avatar"), "Outbox Lobby", 2, 2)
+	if err != nil {
+		t.Fatalf("Expected the lobby to be created, got %v", err)
+	}
+
+	if stored := repo.Memory[response.AccessCode].Outbox; len(stored) != 1 || stored[0].Type != LobbyCreated {
+		t.Fatalf("Expected lobby_created to stay in the outbox, got %+v", stored)
+	}
+
+	if _, err := service.JoinLobby(ctx, response.AccessCode, newPlayer("Player")); err != nil {
+		t.Fatalf("Expected the player to join, got %v", err)
+	}
+
+	events, err := service.Events(ctx, response.AccessCode)
+	if err != nil {
+		t.Fatalf("Expected the event log, got %v", err)
+	}
+
+	if len(events) != 2 || events[0].Type != LobbyCreated || events[1].Type != PlayerJoined {
+		t.Fatalf("Expected lobby_created and player_joined once each, got %+v", events)
+	}
+
+	if err := service.VerifyEventLog(ctx, response.AccessCode); err != nil {
+		t.Errorf("Expected the event log to match the lobby, got %v", err)
+	}
+}
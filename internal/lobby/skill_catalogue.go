@@ -0,0 +1,142 @@
+package lobby
+
+import "github.com/paq-devs/paq-be-rpg/internal/profile"
+
+// SkillDefinition is a skill players of the lobby can declare. Profiles refer
+// to it by ID.
+type SkillDefinition struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`               // shown to the players, the ID when empty
+	Category string `json:"category,omitempty"` // groups skills of the same track
+	Weight   int    `json:"weight,omitempty"`   // used by the weighted priority when it has no weights of its own
+}
+
+// SkillCatalogue lists the hard and soft skills a lobby accepts.
+type SkillCatalogue struct {
+	HardSkills []SkillDefinition `json:"hard_skills"`
+	SoftSkills []SkillDefinition `json:"soft_skills"`
+}
+
+// DefaultSkillCatalogue is made of the skills built into the profile package.
+func DefaultSkillCatalogue() SkillCatalogue {
+	catalogue := SkillCatalogue{
+		HardSkills: make([]SkillDefinition, len(profile.HardSkills)),
+		SoftSkills: make([]SkillDefinition, len(profile.SoftSkills)),
+	}
+
+	for i, skill := range profile.HardSkills {
+		catalogue.HardSkills[i] = SkillDefinition{ID: string(skill), Name: string(skill), Category: "hard"}
+	}
+
+	for i, skill := range profile.SoftSkills {
+		catalogue.SoftSkills[i] = SkillDefinition{ID: string(skill), Name: string(skill), Category: "soft"}
+	}
+
+	return catalogue
+}
+
+// Validate requires at least one skill, IDs that are not empty nor repeated
+// within hard or soft skills, and weights that are not negative.
+func (c SkillCatalogue) Validate() error {
+	if len(c.HardSkills)+len(c.SoftSkills) == 0 {
+		return ErrInvalidSkillCatalogue.WithDetails(map[string]interface{}{"reason": "the catalogue has no skills"})
+	}
+
+	for _, skills := range [][]SkillDefinition{c.HardSkills, c.SoftSkills} {
+		seen := make(map[string]bool, len(skills))
+
+		for _, skill := range skills {
+			if skill.ID == "" || seen[skill.ID] || skill.Weight < 0 {
+				return ErrInvalidSkillCatalogue.WithDetails(map[string]interface{}{"skill": skill})
+			}
+
+			seen[skill.ID] = true
+		}
+	}
+
+	return nil
+}
+
+func (c SkillCatalogue) HasHardSkill(skill profile.HardSkill) bool {
+	return hasSkillDefinition(c.HardSkills, string(skill))
+}
+
+func (c SkillCatalogue) HasSoftSkill(skill profile.SoftSkill) bool {
+	return hasSkillDefinition(c.SoftSkills, string(skill))
+}
+
+// HardSkillWeights returns the weights of the hard skills that have one.
+func (c SkillCatalogue) HardSkillWeights() map[profile.HardSkill]int {
+	weights := make(map[profile.HardSkill]int)
+	for _, skill := range c.HardSkills {
+		if skill.Weight > 0 {
+			weights[profile.HardSkill(skill.ID)] = skill.Weight
+		}
+	}
+
+	return weights
+}
+
+// SoftSkillWeights returns the weights of the soft skills that have one.
+func (c SkillCatalogue) SoftSkillWeights() map[profile.SoftSkill]int {
+	weights := make(map[profile.SoftSkill]int)
+	for _, skill := range c.SoftSkills {
+		if skill.Weight > 0 {
+			weights[profile.SoftSkill(skill.ID)] = skill.Weight
+		}
+	}
+
+	return weights
+}
+
+// checkProfile returns ErrUnknownSkill, listing the skills of p that are not
+// in the catalogue, if there are any.
+func (c SkillCatalogue) checkProfile(p profile.Profile) error {
+	unknownHardSkills := []profile.HardSkill{}
+	for _, skill := range p.HardSkills {
+		if !c.HasHardSkill(skill) {
+			unknownHardSkills = append(unknownHardSkills, skill)
+		}
+	}
+
+	unknownSoftSkills := []profile.SoftSkill{}
+	for _, skill := range p.SoftSkills {
+		if !c.HasSoftSkill(skill) {
+			unknownSoftSkills = append(unknownSoftSkills, skill)
+		}
+	}
+
+	if len(unknownHardSkills)+len(unknownSoftSkills) == 0 {
+		return nil
+	}
+
+	return ErrUnknownSkill.WithDetails(map[string]interface{}{
+		"unknown_hard_skills": unknownHardSkills,
+		"unknown_soft_skills": unknownSoftSkills,
+	})
+}
+
+func hasSkillDefinition(skills []SkillDefinition, id string) bool {
+	for _, skill := range skills {
+		if skill.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithSkillCatalogue sets the skills the players of the lobby can declare.
+func WithSkillCatalogue(catalogue SkillCatalogue) LobbyOption {
+	return func(l *Lobby) {
+		l.SkillCatalogue = catalogue
+	}
+}
+
+func (l *Lobby) skillCatalogue() SkillCatalogue {
+	if len(l.SkillCatalogue.HardSkills)+len(l.SkillCatalogue.SoftSkills) == 0 { // lobbies created before skill catalogues existed
+		return DefaultSkillCatalogue()
+	}
+
+	return l.SkillCatalogue
+}
@@ -0,0 +1,136 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func newDataScienceCatalogue() SkillCatalogue {
+	return SkillCatalogue{
+		HardSkills: []SkillDefinition{
+			{ID: "Statistics", Name: "Estatística", Category: "analytics", Weight: 2},
+			{ID: "MachineLearning", Name: "Machine Learning", Category: "modeling", Weight: 5},
+			{ID: "SQL", Name: "SQL", Category: "engineering"},
+		},
+		SoftSkills: []SkillDefinition{
+			{ID: "Storytelling", Name: "Storytelling", Category: "communication"},
+			{ID: "Leadership", Name: "Liderança", Category: "management", Weight: 1},
+		},
+	}
+}
+
+func TestSkillCatalogue_Validate(t *testing.T) {
+	cases := []struct {
+		catalogue SkillCatalogue
+		valid     bool
+	}{
+		{DefaultSkillCatalogue(), true},
+		{newDataScienceCatalogue(), true},
+		{SkillCatalogue{HardSkills: []SkillDefinition{{ID: "SQL"}}}, true},
+		{SkillCatalogue{}, false},
+		{SkillCatalogue{HardSkills: []SkillDefinition{{Name: "Without ID"}}}, false},
+		{SkillCatalogue{HardSkills: []SkillDefinition{{ID: "SQL"}, {ID: "SQL"}}}, false},
+		{SkillCatalogue{SoftSkills: []SkillDefinition{{ID: "Storytelling", Weight: -1}}}, false},
+	}
+
+	for _, c := range cases {
+		err := c.catalogue.Validate()
+
+		if c.valid && err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", c.catalogue, err)
+		}
+
+		if !c.valid && !errors.Is(err, ErrInvalidSkillCatalogue) {
+			t.Errorf("Expected ErrInvalidSkillCatalogue for %+v, got %v", c.catalogue, err)
+		}
+	}
+}
+
+func TestDefaultSkillCatalogue_HasBuiltInSkills(t *testing.T) {
+	catalogue := DefaultSkillCatalogue()
+
+	for _, skill := range profile.HardSkills {
+		if !catalogue.HasHardSkill(skill) {
+			t.Errorf("Expected the default catalogue to have %v", skill)
+		}
+	}
+
+	for _, skill := range profile.SoftSkills {
+		if !catalogue.HasSoftSkill(skill) {
+			t.Errorf("Expected the default catalogue to have %v", skill)
+		}
+	}
+}
+
+func TestJoin_UnknownSkill(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Catalogue Lobby", 2, 2, WithSkillCatalogue(newDataScienceCatalogue()))
+
+	err := lobby.Join(profile.NewPlayer("Player", "avatar", []profile.HardSkill{"SQL", profile.Marketing}, []profile.SoftSkill{"Storytelling"}))
+
+	if !errors.Is(err, ErrUnknownSkill) {
+		t.Fatalf("Expected ErrUnknownSkill, got %v", err)
+	}
+
+	unknown := err.(*Error).Details["unknown_hard_skills"].([]profile.HardSkill)
+	if len(unknown) != 1 || unknown[0] != profile.Marketing {
+		t.Errorf("Expected only %v to be unknown, got %v", profile.Marketing, unknown)
+	}
+
+	if len(lobby.Players) != 0 {
+		t.Errorf("Expected the player not to join, got %d players", len(lobby.Players))
+	}
+}
+
+func TestJoin_CustomCatalogue(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Catalogue Lobby", 2, 2,
+		WithSkillCatalogue(newDataScienceCatalogue()),
+		WithLeaderRule(LeaderRule{Mode: AnyOfSkills, HardSkills: []profile.HardSkill{"MachineLearning"}}))
+
+	err := lobby.Join(profile.NewPlayer("Modeler", "avatar", []profile.HardSkill{"MachineLearning"}, []profile.SoftSkill{"Storytelling"}))
+
+	if err != nil {
+		t.Fatalf("Expected the player to join, got %v", err)
+	}
+
+	if lobby.Players[0].Role != profile.Leader {
+		t.Errorf("Expected the player to be a leader, got %v", lobby.Players[0].Role)
+	}
+}
+
+func TestLeaderRule_ValidateAgainstCatalogue(t *testing.T) {
+	rule := LeaderRule{Mode: AnyOfSkills, HardSkills: []profile.HardSkill{profile.GDP}}
+
+	if err := rule.Validate(newDataScienceCatalogue()); !errors.Is(err, ErrInvalidLeaderRule) {
+		t.Errorf("Expected ErrInvalidLeaderRule for a skill outside the catalogue, got %v", err)
+	}
+
+	rule = LeaderRule{Mode: AllOfSkills, HardSkills: []profile.HardSkill{"SQL"}, SoftSkills: []profile.SoftSkill{"Storytelling"}}
+
+	if err := rule.Validate(newDataScienceCatalogue()); err != nil {
+		t.Errorf("Expected the rule to be valid, got %v", err)
+	}
+}
+
+func TestSkillCatalogue_Weights(t *testing.T) {
+	catalogue := newDataScienceCatalogue()
+
+	hard := catalogue.HardSkillWeights()
+	if len(hard) != 2 || hard["MachineLearning"] != 5 || hard["Statistics"] != 2 {
+		t.Errorf("Expected the weights of MachineLearning and Statistics, got %v", hard)
+	}
+
+	soft := catalogue.SoftSkillWeights()
+	if len(soft) != 1 || soft["Leadership"] != 1 {
+		t.Errorf("Expected the weight of Leadership, got %v", soft)
+	}
+}
+
+func TestReplay_SkillCatalogue(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Catalogue Lobby", 2, 2, WithSkillCatalogue(newDataScienceCatalogue()))
+	log := &eventLog{lobby: lobby}
+
+	_ = lobby.Join(profile.NewPlayer("Analyst", "avatar", []profile.HardSkill{"Statistics"}, []profile.SoftSkill{"Storytelling"}))
+	log.checkReplay(t)
+}
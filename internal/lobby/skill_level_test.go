@@ -0,0 +1,68 @@
+package lobby
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func TestJoin_SkillLevels(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Levels Lobby", 1, 1)
+
+	player := profile.NewPlayer("Expert", "avatar", []profile.HardSkill{profile.Programming}, []profile.SoftSkill{profile.Empathy})
+	player.SetHardSkillLevel(profile.Programming, profile.Proficiency{Level: 5, Notes: "ships to production every week"})
+
+	if err := lobby.Join(player); err != nil {
+		t.Fatalf("Expected the player to join, got %v", err)
+	}
+
+	joined := lobby.getPlayer(player.ID)
+	if joined.HardSkillLevel(profile.Programming) != 5 {
+		t.Errorf("Expected Programming to be at level 5, got %d", joined.HardSkillLevel(profile.Programming))
+	}
+
+	if joined.SoftSkillLevel(profile.Empathy) != profile.DefaultSkillLevel {
+		t.Errorf("Expected Empathy to be at level %d, got %d", profile.DefaultSkillLevel, joined.SoftSkillLevel(profile.Empathy))
+	}
+}
+
+func TestJoin_InvalidSkillLevel(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Levels Lobby", 2, 2)
+
+	outOfRange := newPlayer("Out of range")
+	outOfRange.SetHardSkillLevel(profile.English, profile.Proficiency{Level: 6})
+
+	undeclared := newPlayer("Undeclared")
+	undeclared.SetSoftSkillLevel(profile.Empathy, profile.Proficiency{Level: 2})
+
+	for _, p := range []profile.Profile{outOfRange, undeclared} {
+		if err := lobby.Join(p); !errors.Is(err, ErrInvalidSkillLevel) {
+			t.Errorf("Expected ErrInvalidSkillLevel for %s, got %v", p.Name, err)
+		}
+	}
+
+	if len(lobby.Players) != 0 {
+		t.Errorf("Expected no player to join, got %d players", len(lobby.Players))
+	}
+}
+
+func TestJoin_MaxSkillsCountsDistinctSkills(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Levels Lobby", 1, 1)
+
+	player := profile.NewPlayer("Repeated", "avatar", []profile.HardSkill{profile.Design, profile.Design}, []profile.SoftSkill{profile.Empathy})
+
+	if err := lobby.Join(player); err != nil {
+		t.Errorf("Expected a repeated skill to count once, got %v", err)
+	}
+}
+
+func TestReplay_SkillLevels(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Levels Lobby", 2, 2)
+	log := &eventLog{lobby: lobby}
+
+	player := newPlayer("Player")
+	player.SetHardSkillLevel(profile.English, profile.Proficiency{Level: 1, Notes: "still learning"})
+	_ = lobby.Join(player)
+	log.checkReplay(t)
+}
@@ -0,0 +1,173 @@
+package lobby
+
+import (
+	"sort"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// LobbyAction names something that can be done to a lobby. Which actions are
+// allowed, by whom and where they lead is declared in the transitions table.
+type LobbyAction string
+
+const (
+	JoinAction               LobbyAction = "join"
+	LeaveAction              LobbyAction = "leave"
+	KickAction               LobbyAction = "kick"
+	RejoinAction             LobbyAction = "rejoin"
+	CloseAction              LobbyAction = "close" // closes the lobby to new players and starts the team creation
+	CancelTeamCreationAction LobbyAction = "cancel_team_creation"
+	CreateTeamsAction        LobbyAction = "create_teams"
+	PromoteAction            LobbyAction = "promote"
+	StartTeamSelectionAction LobbyAction = "start_team_selection"
+	SelectTeamAction         LobbyAction = "select_team"
+	SelectPlayerAction       LobbyAction = "select_player"
+	BalanceTeamsAction       LobbyAction = "balance_teams"
+	AutoPickAction           LobbyAction = "auto_pick"
+	UndoAction               LobbyAction = "undo"
+	StartAction              LobbyAction = "start"
+	PauseAction              LobbyAction = "pause"
+	ResumeAction             LobbyAction = "resume"
+	FinishAction             LobbyAction = "finish"
+	ArchiveAction            LobbyAction = "archive"
+)
+
+// guard rejects an action the actor is not allowed to do right now.
+type guard func(l *Lobby, actor profile.Profile) error
+
+type transition struct {
+	next   []LobbyStatus // statuses the action can lead to, empty when it never changes the status
+	guards []guard
+	system bool // done by the server itself, never offered to a caller
+}
+
+// transitions maps the current status and an action to where the action can
+// lead and who may do it. A pair missing from the table is not allowed.
+var transitions = map[LobbyStatus]map[LobbyAction]transition{
+	Waiting: {
+		JoinAction:   {guards: []guard{notInLobby, notKicked}},
+		LeaveAction:  {guards: []guard{member}},
+		KickAction:   {guards: []guard{master}},
+		RejoinAction: {guards: []guard{notKicked, departed}},
+		CloseAction:  {next: []LobbyStatus{CreatingTeam}, guards: []guard{master}},
+	},
+	CreatingTeam: {
+		CreateTeamsAction:        {next: []LobbyStatus{TeamsCreated, LeaderElection}, system: true},
+		CancelTeamCreationAction: {next: []LobbyStatus{Waiting}, system: true},
+	},
+	LeaderElection: {
+		PromoteAction: {next: []LobbyStatus{TeamsCreated}, guards: []guard{master}},
+	},
+	TeamsCreated: {
+		StartTeamSelectionAction: {next: []LobbyStatus{LeaderTeamSelect}, system: true},
+		BalanceTeamsAction:       {next: []LobbyStatus{ReadyToStart}, guards: []guard{master}},
+	},
+	LeaderTeamSelect: {
+		SelectTeamAction: {next: []LobbyStatus{PlayerSelect}, guards: []guard{choosingNow}},
+		AutoPickAction:   {next: []LobbyStatus{PlayerSelect}, system: true},
+		UndoAction:       {guards: []guard{master, draftToUndo}},
+	},
+	PlayerSelect: {
+		SelectPlayerAction: {next: []LobbyStatus{ReadyToStart}, guards: []guard{choosingNow}},
+		AutoPickAction:     {next: []LobbyStatus{ReadyToStart}, system: true},
+		UndoAction:         {guards: []guard{master, draftToUndo}},
+	},
+	ReadyToStart: {
+		StartAction: {next: []LobbyStatus{InProgress}, guards: []guard{master}},
+		UndoAction:  {next: []LobbyStatus{PlayerSelect}, guards: []guard{master, draftToUndo}},
+	},
+	InProgress: {
+		PauseAction:  {next: []LobbyStatus{Paused}, guards: []guard{master}},
+		FinishAction: {next: []LobbyStatus{Finished}, guards: []guard{master}},
+	},
+	Paused: {
+		ResumeAction: {next: []LobbyStatus{InProgress}, guards: []guard{master}},
+		FinishAction: {next: []LobbyStatus{Finished}, guards: []guard{master}},
+	},
+	Finished: {
+		ArchiveAction: {next: []LobbyStatus{Archived}, guards: []guard{master}},
+	},
+}
+
+// AvailableAction is an action the caller can do in the current status.
+type AvailableAction struct {
+	Action LobbyAction   `json:"action"`
+	Next   []LobbyStatus `json:"next_status"`
+}
+
+// Can returns nil when the actor may do the action in the current status,
+// ErrInvalidStatus when the status does not allow it, or the error of the
+// first guard rejecting the actor.
+func (l *Lobby) Can(action LobbyAction, actor profile.Profile) error {
+	t, err := l.transition(action)
+	if err != nil {
+		return err
+	}
+
+	for _, check := range t.guards {
+		if err := check(l, actor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AvailableActions lists, by name, the actions the actor can do right now.
+func (l *Lobby) AvailableActions(actor profile.Profile) []AvailableAction {
+	available := []AvailableAction{}
+	for action, t := range transitions[l.Status] {
+		if t.system || l.Can(action, actor) != nil {
+			continue
+		}
+
+		next := t.next
+		if next == nil {
+			next = []LobbyStatus{}
+		}
+
+		available = append(available, AvailableAction{Action: action, Next: next})
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].Action < available[j].Action
+	})
+
+	return available
+}
+
+// transition returns the table entry of the action for the current status.
+// Methods done by the server or whose actor is checked elsewhere use it to
+// validate only the status.
+func (l *Lobby) transition(action LobbyAction) (transition, error) {
+	t, ok := transitions[l.Status][action]
+	if !ok {
+		return transition{}, ErrInvalidStatus.WithDetails(map[string]interface{}{"status": l.Status, "action": action})
+	}
+
+	return t, nil
+}
+
+func master(l *Lobby, actor profile.Profile) error {
+	return l.EnsureMaster(actor)
+}
+
+func choosingNow(l *Lobby, actor profile.Profile) error {
+	if l.ChooseControl == nil || l.ChooseControl.ChoosingNow.ID != actor.ID {
+		return ErrNotLeaderTurn
+	}
+
+	return nil
+}
+
+func notInLobby(l *Lobby, actor profile.Profile) error {
+	if actor.ID == "" {
+		return nil
+	}
+
+	if actor.ID == l.Master.ID || l.getPlayer(actor.ID) != nil || l.getMentor(actor.ID) != nil || l.isWaitlisted(actor.ID) {
+		return ErrProfileAlreadyInLobby
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package lobby
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+func actionNames(actions []AvailableAction) []LobbyAction {
+	names := []LobbyAction{}
+	for _, a := range actions {
+		names = append(names, a.Action)
+	}
+
+	return names
+}
+
+func TestAvailableActions_Waiting(t *testing.T) {
+	masterProfile := profile.NewMaster("Master", "avatar")
+	lobby := NewLobby(masterProfile, "Test Lobby", 1, 2)
+
+	playerProfile := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	_ = lobby.Join(playerProfile)
+
+	cases := []struct {
+		name     string
+		actor    profile.Profile
+		expected []LobbyAction
+	}{
+		{"master", masterProfile, []LobbyAction{CloseAction, KickAction}},
+		{"visitor", profile.Profile{}, []LobbyAction{JoinAction}},
+		{"player in the lobby", playerProfile, []LobbyAction{LeaveAction}},
+	}
+
+	for _, c := range cases {
+		actions := lobby.AvailableActions(c.actor)
+
+		if !reflect.DeepEqual(actionNames(actions), c.expected) {
+			t.Errorf("Expected %s to have %v, got %v", c.name, c.expected, actionNames(actions))
+		}
+	}
+
+	closeAction := lobby.AvailableActions(masterProfile)[0]
+	if !reflect.DeepEqual(closeAction.Next, []LobbyStatus{CreatingTeam}) {
+		t.Errorf("Expected close to lead to CreatingTeam, got %v", closeAction.Next)
+	}
+}
+
+func TestAvailableActions_PlayerSelect(t *testing.T) {
+	lobby := newDraftLobby(RoundRobin, 2, 2)
+
+	choosing := lobby.ChooseControl.ChoosingNow
+	other := lobby.Teams[0].Leader
+	if other.ID == choosing.ID {
+		other = lobby.Teams[1].Leader
+	}
+
+	if !reflect.DeepEqual(actionNames(lobby.AvailableActions(choosing)), []LobbyAction{SelectPlayerAction}) {
+		t.Errorf("Expected the choosing leader to select a player, got %v", actionNames(lobby.AvailableActions(choosing)))
+	}
+
+	if len(lobby.AvailableActions(other)) != 0 {
+		t.Errorf("Expected the other leader to have no actions, got %v", actionNames(lobby.AvailableActions(other)))
+	}
+
+	if len(lobby.AvailableActions(lobby.Master)) != 0 {
+		t.Errorf("Expected the master to have no actions, got %v", actionNames(lobby.AvailableActions(lobby.Master)))
+	}
+}
+
+func TestCan(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+
+	if err := lobby.Can(CloseAction, profile.NewMaster("Other", "avatar")); !errors.Is(err, ErrNotMaster) {
+		t.Errorf("Expected ErrNotMaster, got %v", err)
+	}
+
+	if err := lobby.Can(SelectTeamAction, lobby.Master); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+
+	if err := lobby.Can(JoinAction, lobby.Master); !errors.Is(err, ErrProfileAlreadyInLobby) {
+		t.Errorf("Expected ErrProfileAlreadyInLobby, got %v", err)
+	}
+}
+
+// TestTransitions_MatchStatusChanges checks that every status change made by
+// the lobby methods is declared in the transitions table.
+func TestTransitions_MatchStatusChanges(t *testing.T) {
+	declared := func(from LobbyStatus, to LobbyStatus) bool {
+		for _, t := range transitions[from] {
+			for _, next := range t.next {
+				if next == to {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	check := func(lobby *Lobby) {
+		for _, event := range lobby.PullEvents() {
+			if payload, ok := event.Payload.(StatusChangedPayload); ok && !declared(payload.From, payload.To) {
+				t.Errorf("Expected %v -> %v to be in the transitions table", payload.From, payload.To)
+			}
+		}
+	}
+
+	balanced := newBalancedLobby()
+	_ = balanced.BalanceTeams(1)
+	for _, action := range []LobbyAction{StartAction, PauseAction, ResumeAction, FinishAction, ArchiveAction} {
+		_ = balanced.Apply(action)
+	}
+
+	if balanced.Status != Archived {
+		t.Errorf("Expected Status to be Archived, got %v", balanced.Status)
+	}
+
+	check(balanced)
+
+	draft := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+	leader := profile.NewPlayer("Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+	player := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+
+	_ = draft.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = draft.Join(leader)
+	_ = draft.Join(player)
+	_ = draft.StartTeamCreation()
+	_ = draft.CreateTeams()
+	_ = draft.PromoteLeader(leader)
+	_ = draft.StartLeaderTeamSelection()
+
+	leader = draft.ChooseControl.ChoosingNow
+	_ = draft.SelectTeam(leader, 0)
+	_ = draft.SelectPlayer(leader, player.ID)
+
+	if draft.Status != ReadyToStart {
+		t.Errorf("Expected Status to be ReadyToStart, got %v", draft.Status)
+	}
+
+	check(draft)
+}
@@ -1,6 +1,8 @@
 package lobby
 
 import (
+	"strings"
+
 	"github.com/paq-devs/paq-be-rpg/internal/profile"
 )
 
@@ -21,3 +23,55 @@ func NewTeam(id int, mentor profile.Profile) Team {
 		Mentor: mentor,
 	}
 }
+
+func (t *Team) members() []profile.Profile {
+	if t.Leader.ID == "" {
+		return t.Players
+	}
+
+	return append([]profile.Profile{t.Leader}, t.Players...)
+}
+
+// coveredSkills returns the skills of the members, keyed by skillKeys.
+func (t *Team) coveredSkills() map[string]bool {
+	covered := make(map[string]bool)
+	for _, member := range t.members() {
+		for _, skill := range skillKeys(member) {
+			covered[skill] = true
+		}
+	}
+
+	return covered
+}
+
+// skillKeys returns the skills of a profile prefixed by their kind, so hard
+// and soft skills with the same name never collide.
+func skillKeys(p profile.Profile) []string {
+	keys := make([]string, 0, len(p.HardSkills)+len(p.SoftSkills))
+	for _, skill := range p.HardSkills {
+		keys = append(keys, hardSkillPrefix+string(skill))
+	}
+
+	for _, skill := range p.SoftSkills {
+		keys = append(keys, softSkillPrefix+string(skill))
+	}
+
+	return keys
+}
+
+const (
+	hardSkillPrefix = "hard:"
+	softSkillPrefix = "soft:"
+)
+
+func hardSkillFromKey(key string) (profile.HardSkill, bool) {
+	if !strings.HasPrefix(key, hardSkillPrefix) {
+		return "", false
+	}
+
+	return profile.HardSkill(strings.TrimPrefix(key, hardSkillPrefix)), true
+}
+
+func softSkillFromKey(key string) profile.SoftSkill {
+	return profile.SoftSkill(strings.TrimPrefix(key, softSkillPrefix))
+}
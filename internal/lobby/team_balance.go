@@ -0,0 +1,241 @@
+package lobby
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// TeamFormation decides how the teams are filled once they are created.
+type TeamFormation string
+
+const (
+	DraftFormation    TeamFormation = "draft"    // leaders pick their team and players in turns
+	BalancedFormation TeamFormation = "balanced" // the Master fills every team at once with BalanceTeams
+)
+
+func ParseTeamFormation(value string) (TeamFormation, error) {
+	switch formation := TeamFormation(value); formation {
+	case "":
+		return DraftFormation, nil
+	case DraftFormation, BalancedFormation:
+		return formation, nil
+	}
+
+	return "", ErrInvalidTeamFormation.WithDetails(map[string]interface{}{"team_formation": value})
+}
+
+// WithTeamFormation sets how the teams are filled after they are created.
+func WithTeamFormation(formation TeamFormation) LobbyOption {
+	return func(l *Lobby) {
+		l.TeamFormation = formation
+	}
+}
+
+// TeamCoverage summarizes the skills of the members (leader and players) of
+// a team. Missing skills are the ones some member of the lobby has but
+// nobody in the team does.
+type TeamCoverage struct {
+	TeamID            int                       `json:"team_id"`
+	Members           int                       `json:"members"`
+	HardSkills        map[profile.HardSkill]int `json:"hard_skills"`
+	SoftSkills        map[profile.SoftSkill]int `json:"soft_skills"`
+	MissingHardSkills []profile.HardSkill       `json:"missing_hard_skills"`
+	MissingSoftSkills []profile.SoftSkill       `json:"missing_soft_skills"`
+}
+
+type TeamsBalancedPayload struct {
+	Seed     int64          `json:"seed"`
+	Coverage []TeamCoverage `json:"coverage"`
+}
+
+// BalanceTeams is the alternative to the draft: every team without a leader
+// gets one, and the remaining players are spread so team sizes differ by at
+// most one while the skills are covered by as many teams as possible. The
+// same seed on the same lobby always produces the same teams.
+func (l *Lobby) BalanceTeams(seed int64) error {
+	if _, err := l.transition(BalanceTeamsAction); err != nil {
+		return err
+	}
+
+	if len(l.Teams) == 0 {
+		return ErrTeamNotFound
+	}
+
+	random := rand.New(rand.NewSource(seed))
+
+	players := make([]profile.Profile, len(l.Players))
+	copy(players, l.Players)
+	random.Shuffle(len(players), func(i, j int) {
+		players[i], players[j] = players[j], players[i]
+	})
+
+	remaining := make([]profile.Profile, 0, len(players))
+	for _, p := range players {
+		team := l.teamWithoutLeader()
+		if p.Role != profile.Leader || team == nil {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		team.Leader = p
+		l.emit(TeamSelected, TeamSelectedPayload{TeamID: team.ID, Leader: ResponseFromProfile(&p)})
+	}
+
+	// ties go to a team chosen by the seed instead of always the first one
+	teams := make([]*Team, len(l.Teams))
+	copy(teams, l.Teams)
+	random.Shuffle(len(teams), func(i, j int) {
+		teams[i], teams[j] = teams[j], teams[i]
+	})
+
+	// the smallest team takes the player adding the most skills it is
+	// missing, like a draft where every leader picks the best fit
+	frequency := skillFrequency(remaining)
+	for len(remaining) > 0 {
+		team := smallestTeam(teams)
+		i := bestFitIndex(team, remaining, frequency)
+		p := remaining[i]
+		remaining = append(remaining[:i], remaining[i+1:]...)
+
+		team.Players = append(team.Players, p)
+		l.emit(PlayerSelected, PlayerSelectedPayload{TeamID: team.ID, LeaderID: team.Leader.ID, Player: ResponseFromProfile(&p)})
+	}
+
+	l.Players = []profile.Profile{}
+	l.ChooseControl = nil
+	l.emit(TeamsBalanced, TeamsBalancedPayload{Seed: seed, Coverage: l.TeamCoverage()})
+	l.setStatus(ReadyToStart)
+
+	return nil
+}
+
+// TeamCoverage returns the skill coverage of every team, in team order.
+func (l *Lobby) TeamCoverage() []TeamCoverage {
+	pool := make(map[string]bool)
+	for _, team := range l.Teams {
+		for skill := range team.coveredSkills() {
+			pool[skill] = true
+		}
+	}
+
+	coverage := make([]TeamCoverage, 0, len(l.Teams))
+	for _, team := range l.Teams {
+		summary := TeamCoverage{
+			TeamID:            team.ID,
+			HardSkills:        make(map[profile.HardSkill]int),
+			SoftSkills:        make(map[profile.SoftSkill]int),
+			MissingHardSkills: []profile.HardSkill{},
+			MissingSoftSkills: []profile.SoftSkill{},
+		}
+
+		for _, member := range team.members() {
+			summary.Members++
+
+			for _, skill := range member.HardSkills {
+				summary.HardSkills[skill]++
+			}
+
+			for _, skill := range member.SoftSkills {
+				summary.SoftSkills[skill]++
+			}
+		}
+
+		covered := team.coveredSkills()
+		for _, skill := range sortedSkills(pool) {
+			if covered[skill] {
+				continue
+			}
+
+			if hard, ok := hardSkillFromKey(skill); ok {
+				summary.MissingHardSkills = append(summary.MissingHardSkills, hard)
+			} else {
+				summary.MissingSoftSkills = append(summary.MissingSoftSkills, softSkillFromKey(skill))
+			}
+		}
+
+		coverage = append(coverage, summary)
+	}
+
+	return coverage
+}
+
+func (l *Lobby) teamWithoutLeader() *Team {
+	for _, team := range l.Teams {
+		if team.Leader.ID == "" {
+			return team
+		}
+	}
+
+	return nil
+}
+
+func smallestTeam(teams []*Team) *Team {
+	smallest := teams[0]
+	for _, team := range teams {
+		if len(team.Players) < len(smallest.Players) {
+			smallest = team
+		}
+	}
+
+	return smallest
+}
+
+// bestFitIndex returns the index of the player adding the most skills the
+// team is missing, the one holding the rarest skill winning ties.
+func bestFitIndex(team *Team, players []profile.Profile, frequency map[string]int) int {
+	covered := team.coveredSkills()
+
+	best, bestGain, bestRarity := 0, -1, 0
+	for i, p := range players {
+		gain := 0
+		for _, skill := range skillKeys(p) {
+			if !covered[skill] {
+				gain++
+			}
+		}
+
+		rarity := rarestSkill(p, frequency)
+		if gain > bestGain || (gain == bestGain && rarity < bestRarity) {
+			best, bestGain, bestRarity = i, gain, rarity
+		}
+	}
+
+	return best
+}
+
+func skillFrequency(players []profile.Profile) map[string]int {
+	frequency := make(map[string]int)
+	for _, p := range players {
+		for _, skill := range skillKeys(p) {
+			frequency[skill]++
+		}
+	}
+
+	return frequency
+}
+
+// rarestSkill returns how many players share the rarest skill of p. Players
+// without skills come last.
+func rarestSkill(p profile.Profile, frequency map[string]int) int {
+	rarest := math.MaxInt32
+	for _, skill := range skillKeys(p) {
+		if frequency[skill] < rarest {
+			rarest = frequency[skill]
+		}
+	}
+
+	return rarest
+}
+
+func sortedSkills(skills map[string]bool) []string {
+	keys := make([]string, 0, len(skills))
+	for skill := range skills {
+		keys = append(keys, skill)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,221 @@
+package lobby
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/paq-devs/paq-be-rpg/internal/profile"
+)
+
+// newBalancedLobby returns a lobby in TeamsCreated with three teams, three
+// leaders and eight players: three programmers, three designers and two
+// players whose skills nobody else has.
+func newBalancedLobby() *Lobby {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Balanced Lobby", 2, 2, WithTeamFormation(BalancedFormation))
+
+	for i := 0; i < 3; i++ {
+		_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+		_ = lobby.Join(profile.NewPlayer("Leader", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Leadership}))
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = lobby.Join(profile.NewPlayer("Programmer", "avatar", []profile.HardSkill{profile.Programming}, []profile.SoftSkill{profile.Communication}))
+		_ = lobby.Join(profile.NewPlayer("Designer", "avatar", []profile.HardSkill{profile.Design}, []profile.SoftSkill{profile.Creativity}))
+	}
+
+	_ = lobby.Join(profile.NewPlayer("Marketing", "avatar", []profile.HardSkill{profile.Marketing}, []profile.SoftSkill{profile.Empathy}))
+	_ = lobby.Join(profile.NewPlayer("IA", "avatar", []profile.HardSkill{profile.IA}, []profile.SoftSkill{profile.Organization}))
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+
+	return lobby
+}
+
+func TestParseTeamFormation(t *testing.T) {
+	cases := map[string]TeamFormation{
+		"":         DraftFormation,
+		"draft":    DraftFormation,
+		"balanced": BalancedFormation,
+	}
+
+	for value, expected := range cases {
+		formation, err := ParseTeamFormation(value)
+
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", value, err)
+		}
+
+		if formation != expected {
+			t.Errorf("Expected %q to parse to %v, got %v", value, expected, formation)
+		}
+	}
+
+	_, err := ParseTeamFormation("random")
+
+	if !errors.Is(err, ErrInvalidTeamFormation) {
+		t.Errorf("Expected ErrInvalidTeamFormation, got %v", err)
+	}
+}
+
+func TestBalanceTeams_WhenNotInTeamsCreated(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Balanced Lobby", 1, 2)
+
+	err := lobby.BalanceTeams(1)
+
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestBalanceTeams(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		lobby := newBalancedLobby()
+
+		if lobby.Status != TeamsCreated {
+			t.Fatalf("Expected Status to be TeamsCreated, got %v", lobby.Status)
+		}
+
+		err := lobby.BalanceTeams(seed)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if lobby.Status != ReadyToStart {
+			t.Errorf("Expected Status to be ReadyToStart, got %v", lobby.Status)
+		}
+
+		if len(lobby.Players) != 0 {
+			t.Errorf("Expected every player to be in a team, got %d left", len(lobby.Players))
+		}
+
+		smallest, largest, total := len(lobby.Teams[0].Players), 0, 0
+		for _, team := range lobby.Teams {
+			if team.Leader.ID == "" {
+				t.Errorf("Expected team %d to have a leader", team.ID)
+			}
+
+			if len(team.Players) < smallest {
+				smallest = len(team.Players)
+			}
+
+			if len(team.Players) > largest {
+				largest = len(team.Players)
+			}
+
+			total += len(team.Players)
+		}
+
+		if total != 8 {
+			t.Errorf("Expected 8 players in the teams, got %d", total)
+		}
+
+		if largest-smallest > 1 {
+			t.Errorf("Expected team sizes within 1 of each other, got %d and %d with seed %d", smallest, largest, seed)
+		}
+
+		for _, coverage := range lobby.TeamCoverage() {
+			if coverage.HardSkills[profile.Programming] == 0 || coverage.HardSkills[profile.Design] == 0 {
+				t.Errorf("Expected team %d to have Programming and Design with seed %d, got %v", coverage.TeamID, seed, coverage.HardSkills)
+			}
+		}
+	}
+}
+
+func TestBalanceTeams_IsDeterministic(t *testing.T) {
+	teamsOf := func(lobby *Lobby) map[int][]string {
+		teams := make(map[int][]string)
+		for _, team := range lobby.Teams {
+			teams[team.ID] = append(teams[team.ID], team.Leader.ID)
+			for _, p := range team.Players {
+				teams[team.ID] = append(teams[team.ID], p.ID)
+			}
+		}
+
+		return teams
+	}
+
+	lobby := newBalancedLobby()
+	copied := NewLobby(lobby.Master, lobby.Name, lobby.MaxHardSkills, lobby.MaxSoftSkills)
+	copied.Status = TeamsCreated
+	copied.Players = append([]profile.Profile{}, lobby.Players...)
+	copied.Mentors = append([]profile.Profile{}, lobby.Mentors...)
+	copied.buildTeams()
+
+	_ = lobby.BalanceTeams(42)
+	_ = copied.BalanceTeams(42)
+
+	if !reflect.DeepEqual(teamsOf(lobby), teamsOf(copied)) {
+		t.Errorf("Expected the same teams for the same seed, got %v and %v", teamsOf(lobby), teamsOf(copied))
+	}
+}
+
+func TestTeamCoverage(t *testing.T) {
+	lobby := newBalancedLobby()
+	_ = lobby.BalanceTeams(7)
+
+	coverage := lobby.TeamCoverage()
+
+	if len(coverage) != 3 {
+		t.Fatalf("Expected coverage for 3 teams, got %d", len(coverage))
+	}
+
+	missingMarketing := 0
+	for i, summary := range coverage {
+		team := lobby.Teams[i]
+
+		if summary.TeamID != team.ID {
+			t.Errorf("Expected coverage of team %d, got %d", team.ID, summary.TeamID)
+		}
+
+		if summary.Members != len(team.Players)+1 {
+			t.Errorf("Expected %d members, got %d", len(team.Players)+1, summary.Members)
+		}
+
+		if summary.HardSkills[profile.English] != 1 {
+			t.Errorf("Expected the leader English to be counted once, got %d", summary.HardSkills[profile.English])
+		}
+
+		for _, skill := range summary.MissingHardSkills {
+			if summary.HardSkills[skill] > 0 {
+				t.Errorf("Expected %v not to be missing in team %d", skill, summary.TeamID)
+			}
+
+			if skill == profile.Marketing {
+				missingMarketing++
+			}
+
+			if skill == profile.GDP {
+				t.Errorf("Expected skills nobody has not to be reported as missing")
+			}
+		}
+	}
+
+	if missingMarketing != 2 {
+		t.Errorf("Expected Marketing to be missing in 2 teams, got %d", missingMarketing)
+	}
+}
+
+func TestPromoteLeader_CreatesTeams(t *testing.T) {
+	lobby := NewLobby(profile.NewMaster("Master", "avatar"), "Test Lobby", 1, 2)
+
+	player := profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication})
+
+	_ = lobby.Join(profile.NewMentor("Mentor", "avatar"))
+	_ = lobby.Join(player)
+	_ = lobby.Join(profile.NewPlayer("Player", "avatar", []profile.HardSkill{profile.English}, []profile.SoftSkill{profile.Communication}))
+
+	_ = lobby.StartTeamCreation()
+	_ = lobby.CreateTeams()
+	_ = lobby.PromoteLeader(player)
+
+	if lobby.Status != TeamsCreated {
+		t.Errorf("Expected Status to be TeamsCreated, got %v", lobby.Status)
+	}
+
+	if len(lobby.Teams) != 1 {
+		t.Errorf("Expected 1 team after the election, got %d", len(lobby.Teams))
+	}
+}
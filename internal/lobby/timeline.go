@@ -0,0 +1,101 @@
+package lobby
+
+import "strconv"
+
+// TimelineEntry is a selection of the draft as seen by the facilitators
+// reviewing it: who chose what, the turn before and after the choice and how
+// long the turn took.
+type TimelineEntry struct {
+	Position       int             `json:"position"`
+	Phase          LobbyStatus     `json:"phase"`
+	Type           ChooseType      `json:"type"`
+	Actor          ProfileResponse `json:"actor"`
+	Target         TimelineTarget  `json:"target"`
+	Before         *TurnState      `json:"before"`
+	After          *TurnState      `json:"after"` // nil once the draft is over
+	Timestamp      int64           `json:"timestamp"`
+	ElapsedSeconds int64           `json:"elapsed_seconds"`
+}
+
+// TimelineTarget is what was chosen: a team for SelectTeam, a player for
+// SelectPlayer.
+type TimelineTarget struct {
+	Kind string `json:"kind"` // "team" or "player"
+	ID   string `json:"id"`
+	Name string `json:"name"` // the name of the player, or of the mentor of the team
+}
+
+// TurnState is the ChooseControl of the lobby at some point of the draft.
+type TurnState struct {
+	Type        ChooseType      `json:"type"`
+	ChoosingNow ProfileResponse `json:"choosing_now"`
+	Deadline    int64           `json:"deadline,omitempty"`
+}
+
+// Timeline returns the selections of the draft in the order they were made,
+// computed from DraftHistory. Undone selections are not part of it. The time
+// a turn took is counted from DraftAction.TurnStartedAt or, for selections
+// stored before it was recorded, from the previous selection or the phase.
+func (l *Lobby) Timeline() []TimelineEntry {
+	timeline := make([]TimelineEntry, len(l.DraftHistory))
+
+	for i, action := range l.DraftHistory {
+		started := action.TurnStartedAt
+		if started == 0 && i > 0 {
+			started = l.DraftHistory[i-1].Timestamp
+		} else if started == 0 {
+			started = l.StatusTimestamps[action.Phase]
+		}
+
+		elapsed := action.Timestamp - started
+		if elapsed < 0 {
+			elapsed = 0
+		}
+
+		timeline[i] = TimelineEntry{
+			Position:       i + 1,
+			Phase:          action.Phase,
+			Type:           action.Type,
+			Actor:          ResponseFromProfile(&action.Leader),
+			Target:         l.timelineTarget(action),
+			Before:         turnBefore(action),
+			Timestamp:      action.Timestamp,
+			ElapsedSeconds: elapsed,
+		}
+
+		if i > 0 {
+			timeline[i-1].After = timeline[i].Before
+		}
+	}
+
+	if last := len(timeline) - 1; last >= 0 && l.ChooseControl != nil {
+		timeline[last].After = &TurnState{
+			Type:        l.ChooseControl.Type,
+			ChoosingNow: ResponseFromProfile(&l.ChooseControl.ChoosingNow),
+			Deadline:    l.ChooseControl.Deadline,
+		}
+	}
+
+	return timeline
+}
+
+func (l *Lobby) timelineTarget(action DraftAction) TimelineTarget {
+	if action.Type == SelectPlayer {
+		return TimelineTarget{Kind: "player", ID: action.Player.ID, Name: action.Player.Name}
+	}
+
+	target := TimelineTarget{Kind: "team", ID: strconv.Itoa(action.TeamID)}
+	if team := l.getTeam(action.TeamID); team != nil {
+		target.Name = team.Mentor.Name
+	}
+
+	return target
+}
+
+func turnBefore(action DraftAction) *TurnState {
+	return &TurnState{
+		Type:        action.Type,
+		ChoosingNow: ResponseFromProfile(&action.Leader),
+		Deadline:    action.Deadline,
+	}
+}
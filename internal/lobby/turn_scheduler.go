@@ -0,0 +1,53 @@
+package lobby
+
+import (
+	"sync"
+	"time"
+)
+
+// turnScheduler keeps one timer per lobby, firing when the deadline of the
+// current turn is reached.
+type turnScheduler struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func newTurnScheduler() *turnScheduler {
+	return &turnScheduler{
+		timers: make(map[string]*time.Timer),
+	}
+}
+
+// schedule replaces the timer of the lobby so fire runs at the deadline, or
+// right away when the deadline already passed.
+func (s *turnScheduler) schedule(accessCode string, deadline int64, fire func(accessCode string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.timers[accessCode]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(time.Unix(deadline, 0)), func() {
+		s.mu.Lock()
+		if s.timers[accessCode] == timer {
+			delete(s.timers, accessCode)
+		}
+		s.mu.Unlock()
+
+		fire(accessCode)
+	})
+
+	s.timers[accessCode] = timer
+}
+
+func (s *turnScheduler) cancel(accessCode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.timers[accessCode]; ok {
+		timer.Stop()
+		delete(s.timers, accessCode)
+	}
+}
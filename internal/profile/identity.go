@@ -0,0 +1,54 @@
+package profile
+
+// Identity is what a participant keeps across lobbies: who it is and what it
+// knows. What depends on the lobby it joins, its Role, JoinTimestamp and
+// SelectionPriority, lives in the Profile it joins with.
+type Identity struct {
+	ID              string
+	Name            string
+	Avatar          string
+	HardSkills      []HardSkill
+	SoftSkills      []SoftSkill
+	HardSkillLevels map[HardSkill]Proficiency
+	SoftSkillLevels map[SoftSkill]Proficiency
+	CreatedAt       int64  // unix timestamp
+	UpdatedAt       int64  // unix timestamp
+	SecretHash      string // SHA-256 of the secret that proves ownership, hex encoded
+}
+
+// Player returns the profile the identity joins a lobby with as a player.
+func (i Identity) Player() Profile {
+	p := NewPlayer(i.Name, i.Avatar, i.HardSkills, i.SoftSkills)
+	p.ID = i.ID
+
+	for skill, proficiency := range i.HardSkillLevels {
+		p.SetHardSkillLevel(skill, proficiency)
+	}
+
+	for skill, proficiency := range i.SoftSkillLevels {
+		p.SetSoftSkillLevel(skill, proficiency)
+	}
+
+	return p
+}
+
+// Mentor returns the profile the identity joins a lobby with as a mentor.
+// Mentors have no skills.
+func (i Identity) Mentor() Profile {
+	p := NewMentor(i.Name, i.Avatar)
+	p.ID = i.ID
+	return p
+}
+
+// Identity returns the identity fields of the profile, without timestamps.
+func (p *Profile) Identity() Identity {
+	return Identity{
+		ID:              p.ID,
+		Name:            p.Name,
+		Avatar:          p.Avatar,
+		HardSkills:      p.HardSkills,
+		SoftSkills:      p.SoftSkills,
+		HardSkillLevels: p.HardSkillLevels,
+		SoftSkillLevels: p.SoftSkillLevels,
+	}
+}
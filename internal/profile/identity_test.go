@@ -0,0 +1,49 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentity_Player(t *testing.T) {
+	player := NewPlayer("Player", "avatar", []HardSkill{Programming}, []SoftSkill{Leadership})
+	player.SetHardSkillLevel(Programming, Proficiency{Level: 5, Notes: "expert"})
+	identity := player.Identity()
+
+	joined := identity.Player()
+
+	if joined.ID != identity.ID {
+		t.Errorf("Expected ID to be %s, got %s", identity.ID, joined.ID)
+	}
+	if joined.Role != Player {
+		t.Errorf("Expected Role to be Player, got %s", joined.Role)
+	}
+	if joined.SelectionPriority != -1 {
+		t.Errorf("Expected SelectionPriority to be -1, got %d", joined.SelectionPriority)
+	}
+	if !reflect.DeepEqual(joined.HardSkillLevels, identity.HardSkillLevels) {
+		t.Errorf("Expected HardSkillLevels to be %+v, got %+v", identity.HardSkillLevels, joined.HardSkillLevels)
+	}
+
+	joined.SetHardSkillLevel(Programming, Proficiency{Level: 1})
+	if identity.HardSkillLevels[Programming].Level != 5 {
+		t.Errorf("Expected the identity not to share its levels with the profile, got %+v", identity.HardSkillLevels)
+	}
+}
+
+func TestIdentity_Mentor(t *testing.T) {
+	player := NewPlayer("Mentor", "avatar", []HardSkill{Programming}, nil)
+	identity := player.Identity()
+
+	mentor := identity.Mentor()
+
+	if mentor.ID != identity.ID {
+		t.Errorf("Expected ID to be %s, got %s", identity.ID, mentor.ID)
+	}
+	if mentor.Role != Mentor {
+		t.Errorf("Expected Role to be Mentor, got %s", mentor.Role)
+	}
+	if len(mentor.HardSkills) != 0 {
+		t.Errorf("Expected a mentor to have no skills, got %v", mentor.HardSkills)
+	}
+}
@@ -17,6 +17,13 @@ const (
 	Programming HardSkill = "Programming"
 )
 
+// HardSkills lists every HardSkill.
+var HardSkills = []HardSkill{IA, GDP, Marketing, English, Design, Programming}
+
+func (s HardSkill) Valid() bool {
+	return hasHardSkill(HardSkills, s)
+}
+
 type SoftSkill string
 
 const (
@@ -30,6 +37,33 @@ const (
 	Proactivity    SoftSkill = "Proactivity"
 )
 
+// SoftSkills lists every SoftSkill.
+var SoftSkills = []SoftSkill{Communication, Creativity, Organization, Empathy, ProblemSolving, Collaboration, Leadership, Proactivity}
+
+func (s SoftSkill) Valid() bool {
+	return hasSoftSkill(SoftSkills, s)
+}
+
+// SkillLevel is how proficient a profile is in one of its skills, from
+// MinSkillLevel, a beginner, to MaxSkillLevel, an expert.
+type SkillLevel int
+
+const (
+	MinSkillLevel     SkillLevel = 1
+	MaxSkillLevel     SkillLevel = 5
+	DefaultSkillLevel SkillLevel = 3 // skills declared without a level
+)
+
+func (l SkillLevel) Valid() bool {
+	return l >= MinSkillLevel && l <= MaxSkillLevel
+}
+
+// Proficiency is the self-assessment of a profile in one of its skills.
+type Proficiency struct {
+	Level SkillLevel
+	Notes string
+}
+
 type Role string
 
 const (
@@ -45,27 +79,28 @@ type Profile struct {
 	Avatar            string
 	HardSkills        []HardSkill
 	SoftSkills        []SoftSkill
+	HardSkillLevels   map[HardSkill]Proficiency
+	SoftSkillLevels   map[SoftSkill]Proficiency
 	Role              Role
 	JoinTimestamp     int64
 	SelectionPriority int // 0 is the highest priority
 }
 
+// NewPlayer creates a Player with every skill at DefaultSkillLevel. Whether
+// the player is a leader is decided by the lobby it joins.
 func NewPlayer(name, avatar string, hardSkills []HardSkill, softSkills []SoftSkill) Profile {
-	role := Player
-
-	if hasSoftSkill(softSkills, Leadership) || hasHardSkill(hardSkills, GDP) {
-		role = Leader
-	}
-
-	return Profile{
+	p := Profile{
 		ID:                uuid.New().String(),
 		Name:              name,
 		Avatar:            avatar,
 		HardSkills:        hardSkills,
 		SoftSkills:        softSkills,
-		Role:              role,
+		Role:              Player,
 		SelectionPriority: -1,
 	}
+
+	p.FillSkillLevels()
+	return p
 }
 
 func NewMaster(name, avatar string) Profile {
@@ -106,6 +141,88 @@ func (p *Profile) HasSoftSkill(skill SoftSkill) bool {
 	return hasSoftSkill(p.SoftSkills, skill)
 }
 
+// HardSkillLevel returns the level of the skill, 0 when the profile does not
+// have it.
+func (p *Profile) HardSkillLevel(skill HardSkill) SkillLevel {
+	if !p.HasHardSkill(skill) {
+		return 0
+	}
+
+	if proficiency, ok := p.HardSkillLevels[skill]; ok {
+		return proficiency.Level
+	}
+
+	return DefaultSkillLevel
+}
+
+// SoftSkillLevel returns the level of the skill, 0 when the profile does not
+// have it.
+func (p *Profile) SoftSkillLevel(skill SoftSkill) SkillLevel {
+	if !p.HasSoftSkill(skill) {
+		return 0
+	}
+
+	if proficiency, ok := p.SoftSkillLevels[skill]; ok {
+		return proficiency.Level
+	}
+
+	return DefaultSkillLevel
+}
+
+// SetHardSkillLevel sets the proficiency of the profile in one of its skills.
+func (p *Profile) SetHardSkillLevel(skill HardSkill, proficiency Proficiency) {
+	if p.HardSkillLevels == nil {
+		p.HardSkillLevels = make(map[HardSkill]Proficiency)
+	}
+
+	p.HardSkillLevels[skill] = proficiency
+}
+
+// SetSoftSkillLevel sets the proficiency of the profile in one of its skills.
+func (p *Profile) SetSoftSkillLevel(skill SoftSkill, proficiency Proficiency) {
+	if p.SoftSkillLevels == nil {
+		p.SoftSkillLevels = make(map[SoftSkill]Proficiency)
+	}
+
+	p.SoftSkillLevels[skill] = proficiency
+}
+
+// FillSkillLevels gives DefaultSkillLevel to the skills without a level, such
+// as the ones of profiles stored before levels existed.
+func (p *Profile) FillSkillLevels() {
+	for _, skill := range p.HardSkills {
+		if _, ok := p.HardSkillLevels[skill]; !ok {
+			p.SetHardSkillLevel(skill, Proficiency{Level: DefaultSkillLevel})
+		}
+	}
+
+	for _, skill := range p.SoftSkills {
+		if _, ok := p.SoftSkillLevels[skill]; !ok {
+			p.SetSoftSkillLevel(skill, Proficiency{Level: DefaultSkillLevel})
+		}
+	}
+}
+
+// CountHardSkills returns how many different hard skills the profile has.
+func (p *Profile) CountHardSkills() int {
+	distinct := make(map[HardSkill]bool, len(p.HardSkills))
+	for _, skill := range p.HardSkills {
+		distinct[skill] = true
+	}
+
+	return len(distinct)
+}
+
+// CountSoftSkills returns how many different soft skills the profile has.
+func (p *Profile) CountSoftSkills() int {
+	distinct := make(map[SoftSkill]bool, len(p.SoftSkills))
+	for _, skill := range p.SoftSkills {
+		distinct[skill] = true
+	}
+
+	return len(distinct)
+}
+
 func hasSoftSkill(skills []SoftSkill, skill SoftSkill) bool {
 	for _, s := range skills {
 		if s == skill {
@@ -36,7 +36,7 @@ func TestNewPlayer(t *testing.T) {
 	}
 }
 
-func TestNewPlayerWithLeaderSkill(t *testing.T) {
+func TestNewPlayerWithLeaderSkill_StaysPlayer(t *testing.T) {
 	name := "Player"
 	avatar := "avatar"
 	hardSkills := []HardSkill{Programming}
@@ -44,8 +44,8 @@ func TestNewPlayerWithLeaderSkill(t *testing.T) {
 
 	profile := NewPlayer(name, avatar, hardSkills, softSkills)
 
-	if profile.Role != Leader {
-		t.Errorf("Expected Role to be Leader, got %s", profile.Role)
+	if profile.Role != Player {
+		t.Errorf("Expected Role to be Player until a lobby makes it a Leader, got %s", profile.Role)
 	}
 
 	if profile.SelectionPriority != -1 {
@@ -53,7 +53,7 @@ func TestNewPlayerWithLeaderSkill(t *testing.T) {
 	}
 }
 
-func TestNewPlayerWithGDPSkill_ThenTurnIntoLeaderRole(t *testing.T) {
+func TestNewPlayerWithGDPSkill_StaysPlayer(t *testing.T) {
 	name := "Player"
 	avatar := "avatar"
 	hardSkills := []HardSkill{GDP}
@@ -61,8 +61,8 @@ func TestNewPlayerWithGDPSkill_ThenTurnIntoLeaderRole(t *testing.T) {
 
 	profile := NewPlayer(name, avatar, hardSkills, softSkills)
 
-	if profile.Role != Leader {
-		t.Errorf("Expected Role to be Leader, got %s", profile.Role)
+	if profile.Role != Player {
+		t.Errorf("Expected Role to be Player until a lobby makes it a Leader, got %s", profile.Role)
 	}
 
 	if profile.SelectionPriority != -1 {
@@ -188,3 +188,69 @@ func TestJoin(t *testing.T) {
 		t.Errorf("Expected JoinTimestamp to be greater than zero")
 	}
 }
+
+func TestNewPlayer_DefaultSkillLevels(t *testing.T) {
+	profile := NewPlayer("Player", "avatar", []HardSkill{Programming}, []SoftSkill{Communication})
+
+	if profile.HardSkillLevel(Programming) != DefaultSkillLevel {
+		t.Errorf("Expected Programming to be at level %d, got %d", DefaultSkillLevel, profile.HardSkillLevel(Programming))
+	}
+	if profile.SoftSkillLevel(Communication) != DefaultSkillLevel {
+		t.Errorf("Expected Communication to be at level %d, got %d", DefaultSkillLevel, profile.SoftSkillLevel(Communication))
+	}
+	if profile.HardSkillLevel(Design) != 0 {
+		t.Errorf("Expected a skill the profile does not have to be at level 0, got %d", profile.HardSkillLevel(Design))
+	}
+}
+
+func TestSetSkillLevel(t *testing.T) {
+	profile := NewPlayer("Player", "avatar", []HardSkill{Programming}, []SoftSkill{Communication})
+
+	profile.SetHardSkillLevel(Programming, Proficiency{Level: MaxSkillLevel, Notes: "ten years of Go"})
+	profile.SetSoftSkillLevel(Communication, Proficiency{Level: MinSkillLevel})
+
+	if profile.HardSkillLevel(Programming) != MaxSkillLevel {
+		t.Errorf("Expected Programming to be at level %d, got %d", MaxSkillLevel, profile.HardSkillLevel(Programming))
+	}
+	if profile.HardSkillLevels[Programming].Notes != "ten years of Go" {
+		t.Errorf("Expected the notes to be kept, got %q", profile.HardSkillLevels[Programming].Notes)
+	}
+	if profile.SoftSkillLevel(Communication) != MinSkillLevel {
+		t.Errorf("Expected Communication to be at level %d, got %d", MinSkillLevel, profile.SoftSkillLevel(Communication))
+	}
+}
+
+func TestFillSkillLevels(t *testing.T) {
+	profile := Profile{HardSkills: []HardSkill{Programming, Design}, SoftSkills: []SoftSkill{Empathy}}
+	profile.SetHardSkillLevel(Programming, Proficiency{Level: 4})
+
+	profile.FillSkillLevels()
+
+	expected := map[HardSkill]Proficiency{Programming: {Level: 4}, Design: {Level: DefaultSkillLevel}}
+	if !reflect.DeepEqual(profile.HardSkillLevels, expected) {
+		t.Errorf("Expected HardSkillLevels to be %+v, got %+v", expected, profile.HardSkillLevels)
+	}
+	if profile.SoftSkillLevels[Empathy].Level != DefaultSkillLevel {
+		t.Errorf("Expected Empathy to be at level %d, got %d", DefaultSkillLevel, profile.SoftSkillLevels[Empathy].Level)
+	}
+}
+
+func TestCountSkills_Distinct(t *testing.T) {
+	profile := NewPlayer("Player", "avatar", []HardSkill{Programming, Programming, Design}, []SoftSkill{Empathy, Empathy})
+
+	if profile.CountHardSkills() != 2 {
+		t.Errorf("Expected 2 hard skills, got %d", profile.CountHardSkills())
+	}
+	if profile.CountSoftSkills() != 1 {
+		t.Errorf("Expected 1 soft skill, got %d", profile.CountSoftSkills())
+	}
+}
+
+func TestSkillLevel_Valid(t *testing.T) {
+	for level := SkillLevel(-1); level <= MaxSkillLevel+1; level++ {
+		expected := level >= 1 && level <= 5
+		if level.Valid() != expected {
+			t.Errorf("Expected Valid of level %d to be %v", level, expected)
+		}
+	}
+}